@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shizuokago/golin/v2/internal/release"
+)
+
+//
+// golinのリリースビルド
+//
+// モジュールのルートで実行します
+//
+//   go run ./_cmd/release -version 2.0.1
+//
+// build以下に各OS、アーキテクチャのZIP、SHA256SUMS、manifest.jsonを作成します
+// 日時はSOURCE_DATE_EPOCH、未指定の場合はコミットの日時を利用する為、
+// 同じコミットからは同じZIPが作成されます
+//
+func main() {
+
+	ver := flag.String("version", "", "release version (e.g. 2.0.1)")
+	rev := flag.String("revision", "", "git revision (default: git rev-parse --short HEAD)")
+	out := flag.String("o", "build", "output directory")
+	targets := flag.String("targets", "", "comma separated GOOS/GOARCH (default: all release targets)")
+	flag.Parse()
+
+	if *ver == "" {
+		fmt.Fprintln(os.Stderr, "release: -version is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, *ver, *rev, *out, *targets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "release: %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, ver, rev, out, targets string) error {
+
+	var err error
+	if rev == "" {
+		rev, err = git(ctx, "rev-parse", "--short", "HEAD")
+		if err != nil {
+			return err
+		}
+	}
+
+	date, err := sourceDate(ctx)
+	if err != nil {
+		return err
+	}
+
+	conf := release.Config{
+		Version:  ver,
+		Revision: rev,
+		Date:     date,
+		Output:   out,
+		Files:    []string{"README.md"},
+	}
+
+	if targets != "" {
+		for _, elm := range strings.Split(targets, ",") {
+			t, err := release.ParseTarget(strings.TrimSpace(elm))
+			if err != nil {
+				return err
+			}
+			conf.Targets = append(conf.Targets, t)
+		}
+	}
+
+	fmt.Println("============================================")
+	fmt.Println("Build Version:", ver)
+	fmt.Println("-Git revision:", rev)
+	fmt.Println("-Date        :", date.Format(time.RFC3339))
+
+	mf, err := release.Build(ctx, &conf)
+	if err != nil {
+		return err
+	}
+
+	for _, art := range mf.Artifacts {
+		fmt.Printf("%s  %s\n", art.SHA256, filepath.Join(out, art.Name))
+	}
+	fmt.Println("Success")
+	fmt.Println("============================================")
+	return nil
+}
+
+// sourceDate is SOURCE_DATE_EPOCH or the commit time
+func sourceDate(ctx context.Context) (time.Time, error) {
+
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		var err error
+		epoch, err = git(ctx, "log", "-1", "--format=%ct")
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	sec, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid epoch %q: %w", epoch, err)
+	}
+	return time.Unix(sec, 0).UTC(), nil
+}
+
+func git(ctx context.Context, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,237 @@
+package golin
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// ArtifactKind is kind of the golang.org/dl leftover
+type ArtifactKind string
+
+const (
+	ArtifactWrapper ArtifactKind = "wrapper" //$GOPATH/bin/go{version}
+	ArtifactSDK     ArtifactKind = "sdk"     //~/sdk/go{version}
+	ArtifactModule  ArtifactKind = "module"  //モジュールキャッシュのgolang.org/dl
+)
+
+// Artifact is file created by golang.org/dl
+//
+// golang.org/dlでのダウンロード時にgolinが作成したルート外のファイルです
+// 削除に失敗した場合もマニフェストに残る為、golin cleanで削除できます
+type Artifact struct {
+	Path      string       `json:"path"`
+	Kind      ArtifactKind `json:"kind"`
+	Version   string       `json:"version,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// CleanResult is result of Manager.Clean
+type CleanResult struct {
+	Removed   []*Artifact //削除した(DryRunの場合は削除する)ファイル
+	Untracked []*Artifact //golang.org/dlのファイルだが記録がない為、削除しないもの
+	DryRun    bool
+}
+
+// goBinDir is directory of go install
+func goBinDir() string {
+	if bin := GetGoEnv("GOBIN"); bin != "" {
+		return bin
+	}
+	paths := filepath.SplitList(GetGoPath())
+	if len(paths) == 0 || paths[0] == "" {
+		return ""
+	}
+	return filepath.Join(paths[0], "bin")
+}
+
+// wrapperPath is golang.org/dl/go{version} command path
+func wrapperPath(v string) string {
+	dir := goBinDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, exeName("go"+v))
+}
+
+// dlModules is golang.org/dl directories in the module cache
+func dlModules() map[string]bool {
+	dirs := make(map[string]bool)
+	cache := GetGoEnv("GOMODCACHE")
+	if cache == "" {
+		return dirs
+	}
+	list, _ := filepath.Glob(filepath.Join(cache, "golang.org", "dl@*"))
+	for _, elm := range list {
+		dirs[elm] = true
+	}
+	return dirs
+}
+
+// trackArtifacts is add the artifacts to the manifest
+func (m *Manager) trackArtifacts(list ...*Artifact) {
+	if m.root == "" || len(list) == 0 {
+		return
+	}
+	now := time.Now()
+	m.updateManifest(func(mf *Manifest) {
+		if mf.Artifacts == nil {
+			mf.Artifacts = make(map[string]*Artifact)
+		}
+		for _, elm := range list {
+			elm.CreatedAt = now
+			mf.Artifacts[elm.Path] = elm
+			m.logger.Debug("track artifact", "path", elm.Path, "kind", elm.Kind)
+		}
+	})
+}
+
+// untrackArtifacts is remove the artifacts from the manifest
+func (m *Manager) untrackArtifacts(paths ...string) {
+	if m.root == "" || len(paths) == 0 {
+		return
+	}
+	m.updateManifest(func(mf *Manifest) {
+		for _, p := range paths {
+			delete(mf.Artifacts, p)
+		}
+	})
+}
+
+//
+// Clean is remove the golang.org/dl leftovers
+//
+// マニフェストに記録したgolang.org/dlのファイル
+// ($GOPATH/bin/go{version}、~/sdk/go{version}、モジュールキャッシュのgolang.org/dl)を削除します
+// golang.org/dlでインストールしたバージョンの記録のないgo{version}、~/sdk/go{version}は
+// ユーザが作成した可能性がある為、削除せずにUntrackedとして返します
+// dryRunの場合は削除せずに対象のみ返します
+//
+func (m *Manager) Clean(ctx context.Context, dryRun bool) (*CleanResult, error) {
+
+	mf, err := m.Manifest()
+	if err != nil {
+		return nil, xerrors.Errorf("Manifest(): %w", err)
+	}
+
+	targets := make([]*Artifact, 0, len(mf.Artifacts))
+	for _, elm := range mf.Artifacts {
+		targets = append(targets, elm)
+	}
+
+	rtn := CleanResult{
+		Removed:   make([]*Artifact, 0, len(targets)),
+		Untracked: make([]*Artifact, 0),
+		DryRun:    dryRun,
+	}
+
+	//golang.org/dlでインストールしたバージョンのラッパー、SDKで記録のないもの
+	for dir, elm := range mf.SDKs {
+		if elm.Method != MethodDownload {
+			continue
+		}
+		candidates := []*Artifact{
+			{Path: wrapperPath(dir), Kind: ArtifactWrapper, Version: dir},
+			{Path: getSDKPath(dir), Kind: ArtifactSDK, Version: dir},
+		}
+		for _, a := range candidates {
+			if a.Path == "" || mf.Artifacts[a.Path] != nil {
+				continue
+			}
+			if _, err := os.Lstat(a.Path); err == nil {
+				rtn.Untracked = append(rtn.Untracked, a)
+			}
+		}
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Path < targets[j].Path
+	})
+	sort.Slice(rtn.Untracked, func(i, j int) bool {
+		return rtn.Untracked[i].Path < rtn.Untracked[j].Path
+	})
+
+	removed := make([]string, 0, len(targets))
+	for _, elm := range targets {
+		if err := ctx.Err(); err != nil {
+			return &rtn, classify(ErrCancelled, xerrors.Errorf("clean canceled: %w", err))
+		}
+
+		_, err := os.Lstat(elm.Path)
+		exists := err == nil
+		if exists {
+			if dryRun {
+				rtn.Removed = append(rtn.Removed, elm)
+				continue
+			}
+			err = removeArtifact(elm)
+			if err != nil {
+				m.untrackArtifacts(removed...)
+				return &rtn, classifyPermission(xerrors.Errorf("remove %s: %w", elm.Path, err))
+			}
+			m.logger.Info("removed", "path", elm.Path, "kind", elm.Kind)
+			rtn.Removed = append(rtn.Removed, elm)
+		}
+		//存在しない記録も削除
+		if !dryRun {
+			removed = append(removed, elm.Path)
+		}
+	}
+
+	m.untrackArtifacts(removed...)
+	return &rtn, nil
+}
+
+//
+// removeArtifact is remove the file or directory
+//
+// モジュールキャッシュは読み込み専用の為、書き込み権限を付けてから削除します
+//
+func removeArtifact(a *Artifact) error {
+	if a.Kind == ArtifactModule {
+		filepath.WalkDir(a.Path, func(p string, d fs.DirEntry, err error) error {
+			if err == nil && d.IsDir() {
+				os.Chmod(p, 0755)
+			}
+			return nil
+		})
+	}
+	err := os.RemoveAll(a.Path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+//
+// downloadArtifacts is artifacts created by the download
+//
+// ダウンロード前の状態(wrapper、sdkが存在したか、モジュールキャッシュ)と比較し、
+// golinが作成したファイルのみを返します
+//
+func downloadArtifacts(v, wrapper string, wrapperExists, sdkExists bool, modules map[string]bool) []*Artifact {
+
+	list := make([]*Artifact, 0, 3)
+	if wrapper != "" && !wrapperExists {
+		if _, err := os.Lstat(wrapper); err == nil {
+			list = append(list, &Artifact{Path: wrapper, Kind: ArtifactWrapper, Version: v})
+		}
+	}
+	if sdk := getSDKPath(v); sdk != "" && !sdkExists {
+		list = append(list, &Artifact{Path: sdk, Kind: ArtifactSDK, Version: v})
+	}
+	for p := range dlModules() {
+		if !modules[p] {
+			list = append(list, &Artifact{Path: p, Kind: ArtifactModule,
+				Version: strings.TrimPrefix(filepath.Base(p), "dl@")})
+		}
+	}
+	return list
+}
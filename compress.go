@@ -3,19 +3,21 @@ package golin
 import (
 	"archive/tar"
 	"archive/zip"
-	"bytes"
 	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
+	"io/fs"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/cheggaaa/pb/v3"
 	"golang.org/x/xerrors"
 )
 
@@ -36,125 +38,512 @@ func getCompressType(n string) CompressType {
 	return CompressNotSupported
 }
 
-func Compress(w io.Writer, files ...string) error {
+//
+// CompressOptions is Compress options
+//
+// Format   CompressZip(デフォルト)またはCompressTarGz
+// BaseDir  アーカイブ内の名称の基準ディレクトリ(指定したファイルもこのディレクトリからの相対パス)
+// Prefix   アーカイブ内の名称の先頭に付与するディレクトリ(例: go)
+// Exclude  除外するファイルのパターン(path.Matchの形式、名称とファイル名に適用)
+// FollowSymlinks シンボリックリンクを辿ってリンク先の内容を格納する
+//
+type CompressOptions struct {
+	Format         CompressType
+	BaseDir        string
+	Prefix         string
+	Exclude        []string
+	FollowSymlinks bool
+}
+
+//
+// Compress is create archive
+//
+// ファイルとディレクトリ(再帰的)をアーカイブに格納します
+// ファイルはメモリに読み込まずにストリームで書き込みます
+// シンボリックリンクはFollowSymlinksを指定しない限りリンクとして格納します
+//
+func Compress(w io.Writer, opts *CompressOptions, files ...string) error {
+
+	if opts == nil {
+		opts = &CompressOptions{}
+	}
 
-	zw := zip.NewWriter(w)
-	defer zw.Close()
+	var aw archiveWriter
+	switch opts.Format {
+	case CompressZip:
+		aw = &zipWriter{w: zip.NewWriter(w)}
+	case CompressTarGz:
+		gw := gzip.NewWriter(w)
+		aw = &tarGzWriter{gw: gw, w: tar.NewWriter(gw)}
+	default:
+		return xerrors.Errorf("compress not supported: %d", opts.Format)
+	}
 
 	for _, name := range files {
-		err := addFiles(zw, name)
+		p := name
+		if opts.BaseDir != "" && !filepath.IsAbs(p) {
+			p = filepath.Join(opts.BaseDir, p)
+		}
+		err := opts.addFiles(aw, p)
 		if err != nil {
-			return xerrors.Errorf("addFile() error: %w", err)
+			aw.Close()
+			return xerrors.Errorf("addFiles() error: %w", err)
 		}
 	}
 
+	err := aw.Close()
+	if err != nil {
+		return xerrors.Errorf("archive Close() error: %w", err)
+	}
 	return nil
 }
 
-func addFiles(w *zip.Writer, name string) error {
-	info, err := os.Stat(name)
-	if err != nil {
-		return xerrors.Errorf("os.Stat(): %w", err)
+// archiveName is name in the archive
+func (opts *CompressOptions) archiveName(p string) (string, error) {
+	name := p
+	if opts.BaseDir != "" {
+		rel, err := filepath.Rel(opts.BaseDir, p)
+		if err != nil {
+			return "", xerrors.Errorf("filepath.Rel(): %w", err)
+		}
+		if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", xerrors.Errorf("%s is outside of %s", p, opts.BaseDir)
+		}
+		name = rel
 	}
+	name = strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(name)), "/")
+	if opts.Prefix != "" {
+		name = path.Join(opts.Prefix, name)
+	}
+	return name, nil
+}
 
-	if info.IsDir() {
-		infos, err := ioutil.ReadDir(name)
-		if err != nil {
-			return xerrors.Errorf("ioutil.ReadDir() error: %w", err)
+// excluded is name matches the exclude patterns
+func (opts *CompressOptions) excluded(name string) bool {
+	for _, pattern := range opts.Exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(name)); ok {
+			return true
 		}
-		for _, elm := range infos {
-			err := addFiles(w, elm.Name())
+	}
+	return false
+}
+
+func (opts *CompressOptions) addFiles(w archiveWriter, p string) error {
+
+	name, err := opts.archiveName(p)
+	if err != nil {
+		return err
+	}
+	if name != "" && opts.excluded(name) {
+		return nil
+	}
+
+	info, err := os.Lstat(p)
+	if err != nil {
+		return xerrors.Errorf("os.Lstat(): %w", err)
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		if !opts.FollowSymlinks {
+			link, err := os.Readlink(p)
 			if err != nil {
-				return xerrors.Errorf("addFile() error: %w", err)
+				return xerrors.Errorf("os.Readlink(): %w", err)
 			}
+			return w.writeSymlink(name, info, link)
+		}
+		info, err = os.Stat(p)
+		if err != nil {
+			return xerrors.Errorf("os.Stat(): %w", err)
+		}
+	}
+
+	if !info.IsDir() {
+		return addFile(w, p, name, info)
+	}
+
+	//BaseDir自身はエントリを作成しない
+	if name != "" {
+		err = w.writeDir(name, info)
+		if err != nil {
+			return err
 		}
-	} else {
-		err = addFile(w, name)
+	}
+
+	entries, err := os.ReadDir(p)
+	if err != nil {
+		return xerrors.Errorf("os.ReadDir() error: %w", err)
+	}
+	for _, elm := range entries {
+		err := opts.addFiles(w, filepath.Join(p, elm.Name()))
 		if err != nil {
-			return xerrors.Errorf("addFile() error: %w", err)
+			return err
 		}
 	}
 	return nil
 }
 
-func addFile(w *zip.Writer, name string) error {
+func addFile(w archiveWriter, p, name string, info os.FileInfo) error {
 
-	data, err := ioutil.ReadFile(name)
+	if !info.Mode().IsRegular() {
+		return xerrors.Errorf("%s is not a regular file", p)
+	}
+
+	f, err := os.Open(p)
 	if err != nil {
-		return xerrors.Errorf("ioutil.ReadFile() error: %w", err)
+		return xerrors.Errorf("os.Open() error: %w", err)
 	}
+	defer f.Close()
 
-	info, err := os.Lstat(name)
+	err = w.writeFile(name, info, f)
 	if err != nil {
-		return xerrors.Errorf("os.Lstat() error: %w", err)
+		return xerrors.Errorf("%s: %w", name, err)
 	}
+	return nil
+}
+
+// archiveWriter is zip or tar.gz writer
+type archiveWriter interface {
+	writeDir(name string, info os.FileInfo) error
+	writeFile(name string, info os.FileInfo, r io.Reader) error
+	writeSymlink(name string, info os.FileInfo, link string) error
+	Close() error
+}
 
+type zipWriter struct {
+	w *zip.Writer
+}
+
+func (z *zipWriter) create(name string, info os.FileInfo, method uint16) (io.Writer, error) {
 	h, err := zip.FileInfoHeader(info)
 	if err != nil {
-		return xerrors.Errorf("zip.FileInfoHeader() error: %w", err)
+		return nil, xerrors.Errorf("zip.FileInfoHeader() error: %w", err)
 	}
-
 	h.Name = name
-	h.Method = zip.Deflate
+	h.Method = method
+	w, err := z.w.CreateHeader(h)
+	if err != nil {
+		return nil, xerrors.Errorf("writer CreateHeader() error: %w", err)
+	}
+	return w, nil
+}
 
-	writer, err := w.CreateHeader(h)
+func (z *zipWriter) writeDir(name string, info os.FileInfo) error {
+	_, err := z.create(name+"/", info, zip.Store)
+	return err
+}
+
+func (z *zipWriter) writeFile(name string, info os.FileInfo, r io.Reader) error {
+	w, err := z.create(name, info, zip.Deflate)
 	if err != nil {
-		return xerrors.Errorf("writer CreateHeader() error: %w", err)
+		return err
 	}
+	_, err = io.Copy(w, r)
+	if err != nil {
+		return xerrors.Errorf("writer Write() error: %w", err)
+	}
+	return nil
+}
 
-	_, err = writer.Write(data)
+// writeSymlink はリンク先をファイルの内容として格納します(Info-ZIPと同じ形式)
+func (z *zipWriter) writeSymlink(name string, info os.FileInfo, link string) error {
+	w, err := z.create(name, info, zip.Store)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, link)
 	if err != nil {
 		return xerrors.Errorf("writer Write() error: %w", err)
 	}
+	return nil
+}
 
+func (z *zipWriter) Close() error {
+	return z.w.Close()
+}
+
+type tarGzWriter struct {
+	gw *gzip.Writer
+	w  *tar.Writer
+}
+
+func (t *tarGzWriter) header(name string, info os.FileInfo, link string) error {
+	h, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return xerrors.Errorf("tar.FileInfoHeader() error: %w", err)
+	}
+	h.Name = name
+	//実行環境のユーザ名等を含めない
+	h.Uid, h.Gid, h.Uname, h.Gname = 0, 0, "", ""
+	err = t.w.WriteHeader(h)
+	if err != nil {
+		return xerrors.Errorf("tar WriteHeader() error: %w", err)
+	}
+	return nil
+}
+
+func (t *tarGzWriter) writeDir(name string, info os.FileInfo) error {
+	return t.header(name+"/", info, "")
+}
+
+func (t *tarGzWriter) writeFile(name string, info os.FileInfo, r io.Reader) error {
+	err := t.header(name, info, "")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(t.w, r)
+	if err != nil {
+		return xerrors.Errorf("tar Write() error: %w", err)
+	}
 	return nil
 }
 
+func (t *tarGzWriter) writeSymlink(name string, info os.FileInfo, link string) error {
+	return t.header(name, info, link)
+}
+
+func (t *tarGzWriter) Close() error {
+	err := t.w.Close()
+	if err != nil {
+		t.gw.Close()
+		return err
+	}
+	return t.gw.Close()
+}
+
+//
+// DecompressURL is download and decompress archive
+//
+// アーカイブをダウンロードしてdirに展開します
+//
 func DecompressURL(url string, dir string) error {
+	m, err := defaultManager()
+	if err != nil {
+		return xerrors.Errorf("defaultManager(): %w", err)
+	}
+	_, err = m.decompressURL(context.Background(), url, dir)
+	return err
+}
 
-	resp, err := http.Get(url)
+// releasePrefix is top directory of the Go release archives
+const releasePrefix = "go"
+
+//
+// Decompress is decompress the archive
+//
+// Compressと同じオプション(FormatとPrefix)でdirに展開します
+// Prefixを指定した場合はそのディレクトリを除き、Prefix以外のエントリはエラーにします
+// Prefixを指定しない場合はアーカイブ内の名称のままdirに展開します
+// Compressで作成したアーカイブを同じオプションで元のディレクトリに戻すことができます
+// dirは存在しない必要があります
+//
+func (m *Manager) Decompress(ctx context.Context, r io.Reader, opts *CompressOptions, dir string) error {
+	if opts == nil {
+		opts = &CompressOptions{}
+	}
+	//Compressと同じくpath.Joinの形式にする
+	prefix := strings.TrimPrefix(path.Clean("/"+opts.Prefix), "/")
+	switch opts.Format {
+	case CompressZip:
+		//zipは末尾から読み込む為、一時ファイルに書き出す
+		tmp, err := os.CreateTemp("", "golin-archive-*")
+		if err != nil {
+			return xerrors.Errorf("os.CreateTemp(): %w", err)
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+
+		size, err := io.Copy(tmp, r)
+		if err != nil {
+			return classifyContext(ctx, xerrors.Errorf("io.Copy(): %w", err))
+		}
+		return m.decompressZip(ctx, tmp, size, dir, prefix)
+	case CompressTarGz:
+		return m.decompressTarGz(ctx, r, dir, prefix)
+	}
+	return xerrors.Errorf("decompress not supported: %d", opts.Format)
+}
+
+//
+// decompressURL is download, verify and decompress archive
+//
+// 戻り値はダウンロードしたアーカイブのSHA256です
+//
+func (m *Manager) decompressURL(ctx context.Context, url string, dir string) (digest string, rerr error) {
+
+	//途中で失敗、キャンセルした場合は作成途中のディレクトリを削除
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		defer func() {
+			if rerr != nil {
+				os.RemoveAll(dir)
+			}
+		}()
+	}
+
+	resp, err := m.get(ctx, url, ErrVersionNotFound)
 	if err != nil {
-		return xerrors.Errorf("http Get error: %w", err)
+		return "", xerrors.Errorf("http Get error: %w", err)
 	}
 	defer resp.Body.Close()
 
+	//公開されているチェックサム(アーカイブが存在しない場合はErrVersionNotFoundを優先する)
+	sum, err := m.fetchChecksum(ctx, url)
+	if err != nil {
+		return "", xerrors.Errorf("fetchChecksum() error: %w", err)
+	}
+
+	m.logger.Debug("response", "url", url, "status", resp.Status, "content-length", resp.ContentLength)
+
+	start := time.Now()
+	h := sha256.New()
+	body := &countReader{r: io.TeeReader(resp.Body, h)}
+
+	verify := func() error {
+		digest = hex.EncodeToString(h.Sum(nil))
+		if sum == "" {
+			return nil
+		}
+		if digest != sum {
+			return &ChecksumError{URL: url, Expected: sum, Actual: digest}
+		}
+		m.logger.Debug("checksum verified", "url", url, "sha256", digest)
+		return nil
+	}
+
+	//展開前に検証する(一時ファイルに書き出す)
+	tmp, err := os.CreateTemp("", "golin-archive-*")
+	if err != nil {
+		return "", xerrors.Errorf("os.CreateTemp(): %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	_, err = io.Copy(tmp, body)
+	if err != nil {
+		return "", classifyRequest(ctx, xerrors.Errorf("read body error: %w", err))
+	}
+	err = verify()
+	if err != nil {
+		return "", err
+	}
+
 	switch getCompressType(url) {
 	case CompressZip:
-		return decompressZip(resp.Body, dir)
+		err = m.decompressZip(ctx, tmp, body.size, dir, releasePrefix)
+		if err != nil {
+			return "", classifyContext(ctx, err)
+		}
 	case CompressTarGz:
-		return decompressTarGz(resp.Body, dir)
+		_, err = tmp.Seek(0, io.SeekStart)
+		if err != nil {
+			return "", xerrors.Errorf("Seek(): %w", err)
+		}
+		err = m.decompressTarGz(ctx, tmp, dir, releasePrefix)
+		if err != nil {
+			return "", classifyContext(ctx, err)
+		}
 	default:
-		return fmt.Errorf("Decompress NotSupported: %s", url)
+		return "", fmt.Errorf("Decompress NotSupported: %s", url)
 	}
 
-	return fmt.Errorf("Not Reachable.")
+	m.logger.Info("decompressed", "url", url, "path", dir,
+		"size", body.size, "duration", time.Since(start))
+	return digest, nil
 }
 
-func decompressZip(r io.Reader, dir string) error {
+//
+// get is HTTP GET request
+//
+// 404の場合はnotFound、それ以外のエラーはErrNetworkとします
+//
+func (m *Manager) get(ctx context.Context, url string, notFound error) (*http.Response, error) {
 
-	err := os.Mkdir(dir, 0777)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return xerrors.Errorf("make directory error: %w", err)
+		return nil, xerrors.Errorf("http.NewRequest() error: %w", err)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, classifyRequest(ctx, err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	err = xerrors.Errorf("%s: %s", url, resp.Status)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, classify(notFound, err)
+	}
+	return nil, classify(ErrNetwork, err)
+}
+
+//
+// fetchChecksum is archive SHA256
+//
+// アーカイブのURLに.sha256を付与したファイルからチェックサムを取得します
+// 公開されていない場合はErrChecksumMismatchにします
+// SetAllowMissingChecksumを指定した場合のみ警告を出力して空文字を返します(検証しない)
+//
+func (m *Manager) fetchChecksum(ctx context.Context, url string) (string, error) {
+
+	resp, err := m.get(ctx, url+".sha256", ErrVersionNotFound)
+	if errors.Is(err, ErrVersionNotFound) {
+		if !m.allowMissingChecksum {
+			return "", classify(ErrChecksumMismatch, xerrors.Errorf("checksum is not published: %s", url))
+		}
+		m.logger.Warn("checksum is not published", "url", url)
+		return "", nil
+	} else if err != nil {
+		return "", err
 	}
+	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(r)
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
 	if err != nil {
-		return xerrors.Errorf("ioutil.ReadAll() error: %w", err)
+		return "", classifyRequest(ctx, xerrors.Errorf("read checksum: %w", err))
+	}
+
+	fields := strings.Fields(string(b))
+	if len(fields) == 0 {
+		return "", xerrors.Errorf("checksum is empty: %s", url)
 	}
+	return strings.ToLower(fields[0]), nil
+}
 
-	fmt.Println("Downloaded!")
-	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+func (m *Manager) decompressZip(ctx context.Context, r io.ReaderAt, size int64, dir, prefix string) error {
+
+	err := os.Mkdir(dir, 0777)
+	if err != nil {
+		return classifyPermission(xerrors.Errorf("make directory error: %w", err))
+	}
+
+	zr, err := zip.NewReader(r, size)
 	if err != nil {
 		return xerrors.Errorf("zip.NewReader() error: %w", err)
 	}
 
-	fmt.Println("Decompress...")
+	m.logger.Info("decompress", "path", dir, "files", len(zr.File))
 
-	bar := pb.StartNew(len(zr.File))
+	bar := m.startProgress(len(zr.File))
 	for _, f := range zr.File {
 
-		//goを変換
-		fn := filepath.Clean(dir + f.Name[2:])
+		if err := ctx.Err(); err != nil {
+			return classify(ErrCancelled, xerrors.Errorf("decompress canceled: %w", err))
+		}
+
+		fn, err := extractPath(dir, f.Name, prefix)
+		if err != nil {
+			return xerrors.Errorf("extractPath(): %w", err)
+		}
+		err = checkPath(dir, fn)
+		if err != nil {
+			return xerrors.Errorf("checkPath(): %w", err)
+		}
 
 		info := f.FileInfo()
 		if info.IsDir() {
@@ -162,11 +551,23 @@ func decompressZip(r io.Reader, dir string) error {
 			if err != nil {
 				return xerrors.Errorf("make directory error: %w", err)
 			}
+		} else if info.Mode()&os.ModeSymlink != 0 {
+			err = createZipSymlink(dir, f, fn)
+			if err != nil {
+				return xerrors.Errorf("createZipSymlink error: %w", err)
+			}
 		} else {
 			err = createZipFile(f, fn)
 			if err != nil {
 				return xerrors.Errorf("createZipFile error: %w", err)
 			}
+			//作成元によってはパーミッションを持たない
+			if perm := info.Mode().Perm(); perm != 0 {
+				err = os.Chmod(fn, perm)
+				if err != nil {
+					return xerrors.Errorf("os.Chmod(): %w", err)
+				}
+			}
 		}
 		bar.Increment()
 	}
@@ -176,7 +577,110 @@ func decompressZip(r io.Reader, dir string) error {
 	return nil
 }
 
+//
+// extractPath is extract path of the archive entry
+//
+// 先頭のディレクトリ(prefix、Goのリリースはgo/)を除いてdir以下のパスにします
+// prefixの外のエントリはエラーとします
+// 「..」でdirの外に出ることはありません
+//
+func extractPath(dir, name, prefix string) (string, error) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if prefix != "" {
+		if name == prefix {
+			name = ""
+		} else if rest, ok := strings.CutPrefix(name, prefix+"/"); ok {
+			name = rest
+		} else {
+			return "", xerrors.Errorf("%s is not in %s/", name, prefix)
+		}
+	}
+	return filepath.Join(dir, filepath.FromSlash(name)), nil
+}
+
+//
+// checkPath is check the extract path does not pass through symbolic links
+//
+// dirからnまでの各階層をLstatし、シンボリックリンクが存在する場合はエラーにします
+// 先に展開したリンク(a/evil -> /etc)を経由してdirの外に書き込まない為です
+//
+func checkPath(dir, n string) error {
+	rel, err := filepath.Rel(dir, n)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return xerrors.Errorf("path is outside of %s: %s", dir, n)
+	}
+	if rel == "." {
+		return nil
+	}
+
+	p := dir
+	for _, elm := range strings.Split(rel, string(filepath.Separator)) {
+		p = filepath.Join(p, elm)
+		info, err := os.Lstat(p)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return xerrors.Errorf("os.Lstat(): %w", err)
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return xerrors.Errorf("path passes through the symbolic link: %s", p)
+		}
+	}
+	return nil
+}
+
+//
+// checkLink is check the symbolic link target stays in the dir
+//
+// 絶対パス、およびリンクの位置から解決してdirの外になるリンク先はエラーにします
+//
+func checkLink(dir, n, link string) error {
+	if link == "" || path.IsAbs(link) || filepath.IsAbs(link) || filepath.VolumeName(link) != "" {
+		return xerrors.Errorf("invalid symbolic link target: %s -> %q", n, link)
+	}
+	target := filepath.Join(filepath.Dir(n), filepath.FromSlash(link))
+	rel, err := filepath.Rel(dir, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return xerrors.Errorf("symbolic link target is outside of %s: %s -> %q", dir, n, link)
+	}
+	return nil
+}
+
+// createSymlink is symbolic link in the dir
+func createSymlink(dir, n, link string) error {
+	err := checkLink(dir, n, link)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(n), 0777)
+	if err != nil {
+		return xerrors.Errorf("make directory error: %w", err)
+	}
+	return os.Symlink(link, n)
+}
+
+// createZipSymlink is symbolic link of the zip entry
+//
+// ZIPのシンボリックリンクはリンク先を内容として持ちます
+func createZipSymlink(dir string, zf *zip.File, n string) error {
+	f, err := zf.Open()
+	if err != nil {
+		return xerrors.Errorf("zip file open: %w", err)
+	}
+	defer f.Close()
+
+	link, err := io.ReadAll(f)
+	if err != nil {
+		return xerrors.Errorf("zip file read: %w", err)
+	}
+	return createSymlink(dir, n, string(link))
+}
+
 func createZipFile(zf *zip.File, n string) error {
+	err := os.MkdirAll(filepath.Dir(n), 0777)
+	if err != nil {
+		return xerrors.Errorf("make directory error: %w", err)
+	}
 	fo, err := os.Create(n)
 	if err != nil {
 		return xerrors.Errorf("file create: %w", err)
@@ -197,43 +701,60 @@ func createZipFile(zf *zip.File, n string) error {
 	return nil
 }
 
-func decompressTarGz(r io.Reader, dir string) error {
+func (m *Manager) decompressTarGz(ctx context.Context, r io.Reader, dir, prefix string) error {
 
 	err := os.Mkdir(dir, 0777)
 	if err != nil {
-		return xerrors.Errorf("make directory error: %w", err)
+		return classifyPermission(xerrors.Errorf("make directory error: %w", err))
 	}
 
-	fmt.Println("Downloaded!")
-
 	gzr, err := gzip.NewReader(r)
 	if err != nil {
 		return xerrors.Errorf("gzip.NewReader() error: %w", err)
 	}
 	defer gzr.Close()
 
-	fmt.Println("Decompress...")
+	m.logger.Info("decompress", "path", dir)
 
 	tr := tar.NewReader(gzr)
 
-	fmt.Println(time.Now())
-
-	bar := pb.StartNew(10000)
+	bar := m.startProgress(10000)
 	for {
+		if err := ctx.Err(); err != nil {
+			return classify(ErrCancelled, xerrors.Errorf("decompress canceled: %w", err))
+		}
+
 		th, err := tr.Next()
 		if errors.Is(err, io.EOF) {
 			break
+		} else if err != nil {
+			return xerrors.Errorf("tar Next(): %w", err)
 		}
 
-		name := th.Name[2:]
-		//goを変換
-		fn := filepath.Clean(dir + name)
+		//git archiveが付与するコミット情報
+		if th.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+
+		fn, err := extractPath(dir, th.Name, prefix)
+		if err != nil {
+			return xerrors.Errorf("extractPath(): %w", err)
+		}
+		err = checkPath(dir, fn)
+		if err != nil {
+			return xerrors.Errorf("checkPath(): %w", err)
+		}
 
 		if th.Typeflag == tar.TypeDir {
 			err = os.MkdirAll(fn, 0777)
 			if err != nil {
 				return xerrors.Errorf("make directory error: %w", err)
 			}
+		} else if th.Typeflag == tar.TypeSymlink {
+			err = createSymlink(dir, fn, th.Linkname)
+			if err != nil {
+				return xerrors.Errorf("symlink error: %w", err)
+			}
 		} else {
 			err = createTarFile(tr, fn)
 			if err != nil {
@@ -249,13 +770,16 @@ func decompressTarGz(r io.Reader, dir string) error {
 	}
 
 	bar.Finish()
-	fmt.Println(time.Now())
 
 	return nil
 }
 
 func createTarFile(r io.Reader, f string) error {
 
+	err := os.MkdirAll(filepath.Dir(f), 0777)
+	if err != nil {
+		return xerrors.Errorf("make directory error: %w", err)
+	}
 	fo, err := os.Create(f)
 	if err != nil {
 		return xerrors.Errorf("file create: %w", err)
@@ -9,12 +9,17 @@ type Config struct {
 }
 
 const (
-	DefaultLinkName    = "current"                      //作成するリンク名
-	GoGetLink          = "golang.org/dl"                //ダウンロード時のリンク先
-	GitHubDownloadPage = "https://github.com/golang/dl" //GitHub上のバージョンリスト
-	GolangDownloadPage = "https://golang.org/dl"        //install時のダウンロード
+	DefaultLinkName    = "current"                        //作成するリンク名
+	GoGetLink          = "golang.org/dl"                  //ダウンロード時のリンク先
+	GitHubDownloadPage = "https://github.com/golang/dl"   //GitHub上のバージョンリスト
+	GolangDownloadPage = "https://golang.org/dl"          //install時のダウンロード
+	GoSourceRepository = "https://go.googlesource.com/go" //開発版のリポジトリ
+	GoSourceBranch     = "master"                         //開発版のブランチ
 )
 
+// GolinReleaseURL is latest release of golin (GitHub Releases API)
+const GolinReleaseURL = "https://api.github.com/repos/shizuokago/golin/releases/latest"
+
 var gConf *Config = nil
 
 func defaultConfig() *Config {
@@ -1,8 +1,11 @@
 package golin
 
 import (
+	"context"
 	"os"
+	"path/filepath"
 
+	"github.com/shizuokago/golin/v2/i18n"
 	"golang.org/x/xerrors"
 )
 
@@ -10,47 +13,91 @@ import (
 // Create is create symblic link
 //
 // 引数でバージョンを指定します
+// configの設定でManagerを作成し、Switch()を行います
+//
+func Create(v string) error {
+	m, err := defaultManager()
+	if err != nil {
+		return xerrors.Errorf("defaultManager(): %w", err)
+	}
+	_, err = m.Switch(context.Background(), v)
+	return err
+}
+
+//
+// Switch is switch symbolic link to the version
+//
 // GOROOTの確認、権限の確認、パスの準備、リンクの準備(削除)
 // リンクの張り直しを行います
 //
-// BUG(secondarykey): テストがGo1.12にしてないと通らない
-//
-func Create(v string) error {
+func (m *Manager) Switch(ctx context.Context, v string) (*SwitchResult, error) {
+
+	v = canonicalVersion(v)
 
 	//ルートを取得
-	root, err := getRoot(v)
+	root, err := m.getRoot(v)
 	if err != nil {
-		return xerrors.Errorf("getRoot() error: %w", err)
+		return nil, xerrors.Errorf("getRoot() error: %w", err)
 	}
-	//権限チェック
-	err = checkAuthorization(root)
-	if err != nil {
-		return xerrors.Errorf("authorization error: %w", err)
+	//既にリンク先のバージョンの場合(開発版は更新を確認する)
+	path := filepath.Join(root, v)
+	if cur, err := m.Current(ctx); err == nil && cur.Path == path && v != CompileSDK {
+		err = m.applyToolchain(path)
+		if err != nil {
+			return nil, xerrors.Errorf("applyToolchain(): %w", err)
+		}
+		rtn := SwitchResult{
+			Version: NewVersion(v),
+			Path:    path,
+			Link:    cur.Link,
+		}
+		return &rtn, classify(ErrAlreadyCurrent, xerrors.Errorf("%s", v))
+	}
+
+	//権限チェック(展開済みのバージョンはリンクのみ作成する)
+	if _, err := os.Stat(path); err != nil || v == CompileSDK {
+		err = checkAuthorization(root)
+		if err != nil {
+			return nil, xerrors.Errorf("authorization error: %w", err)
+		}
 	}
 
 	//設定前のGoのバージョン表示
-	currentVersion = printGoVersion("Before:")
+	m.goVersion = m.printGoVersion(m.msg.Sprintf(i18n.Before))
 
 	//指定バージョンでパスを作成
-	path, err := readyPath(root, v)
+	path, err = m.readyPath(ctx, root, v)
 	if err != nil {
-		return xerrors.Errorf("ready path: %w", err)
+		return nil, xerrors.Errorf("ready path: %w", err)
 	}
 
-	//シンボリックを準備
-	link, err := readyLink(root)
+	//goコマンドによるツールチェインの切り替えを止める(指定がない場合は元に戻す)
+	err = m.applyToolchain(path)
 	if err != nil {
-		return xerrors.Errorf("ready link: %w", err)
+		return nil, xerrors.Errorf("applyToolchain(): %w", err)
 	}
 
-	//シンボリックリンクを作成
-	err = os.Symlink(path, link)
+	//シンボリックリンクを作成(必要な場合のみ昇格)
+	link, err := m.createLink(ctx, path)
 	if err != nil {
-		return xerrors.Errorf("symlink: %w", err)
+		return nil, xerrors.Errorf("create link: %w", err)
 	}
+	m.logger.Info("switch", "version", v, "path", path, "link", link)
+	m.recordUse(filepath.Base(path))
 
 	//終了したバージョンを作成
-	printGoVersion("After :")
+	after := m.printGoVersion(m.msg.Sprintf(i18n.After))
 
-	return nil
+	rtn := SwitchResult{
+		Version: NewVersion(v),
+		Path:    path,
+		Link:    link,
+		Before:  m.goVersion,
+		After:   after,
+	}
+	//1.21以降はGOTOOLCHAINにより別のバージョンで動作する可能性がある
+	if supportsToolchain(after) {
+		rtn.Toolchain = GetGoEnv("GOTOOLCHAIN")
+	}
+	return &rtn, nil
 }
@@ -0,0 +1,124 @@
+package golin
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+//
+// Current is current linked version
+//
+// シンボリックリンクのリンク先からバージョンを返します
+// PATHのgoコマンドがリンク先のSDKのものかも判定します
+//
+func (m *Manager) Current(ctx context.Context) (*CurrentResult, error) {
+
+	if m.root == "" {
+		return nil, errNoRoot
+	}
+
+	link := m.linkPath()
+	path, err := os.Readlink(link)
+	if err != nil {
+		return nil, classify(ErrVersionNotFound, xerrors.Errorf("os.Readlink(): %w", err))
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.root, path)
+	}
+
+	rtn := CurrentResult{
+		Version: NewVersion(filepath.Base(path)),
+		Path:    path,
+		Link:    link,
+	}
+
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		rtn.Resolved = resolved
+	}
+
+	//PATHのgoコマンド
+	if goCmd, err := exec.LookPath("go"); err == nil {
+		rtn.GoCommand = goCmd
+		rtn.InPath = inSDK(goCmd, path)
+	}
+	return &rtn, nil
+}
+
+//
+// Which is tool path of the installed version
+//
+// インストール済みのバージョンのgo、gofmt、pkg/tool以下のコマンドのパスを返します
+// バージョンの指定がない場合はリンク先のバージョンを対象にします
+//
+func (m *Manager) Which(ctx context.Context, ver, tool string) (string, error) {
+
+	if m.root == "" {
+		return "", errNoRoot
+	}
+
+	if tool == "" || filepath.Base(tool) != tool {
+		return "", xerrors.Errorf("invalid tool: %q", tool)
+	}
+
+	dir := ""
+	if ver == "" {
+		cur, err := m.Current(ctx)
+		if err != nil {
+			return "", xerrors.Errorf("Current(): %w", err)
+		}
+		dir = cur.Path
+	} else {
+		ver = canonicalVersion(ver)
+		if filepath.Base(ver) != ver {
+			return "", xerrors.Errorf("invalid version: %q", ver)
+		}
+		dir = filepath.Join(m.root, ver)
+		if _, err := os.Stat(dir); err != nil {
+			return "", classify(ErrVersionNotFound, xerrors.Errorf("version not installed: %w", err))
+		}
+	}
+
+	candidates := []string{
+		filepath.Join(dir, "bin", exeName(tool)),
+		filepath.Join(dir, "pkg", "tool", runtime.GOOS+"_"+runtime.GOARCH, exeName(tool)),
+	}
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", xerrors.Errorf("tool not found: %s in %s", tool, dir)
+}
+
+//
+// inSDK is command in the SDK check
+//
+// シンボリックリンクを解決した上でコマンドがSDKのbin以下にあるかを判定します
+//
+func inSDK(cmd, sdk string) bool {
+
+	c, err := filepath.EvalSymlinks(cmd)
+	if err != nil {
+		return false
+	}
+	s, err := filepath.EvalSymlinks(sdk)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(c, filepath.Join(s, "bin")+string(filepath.Separator))
+}
+
+// exeName is executable file name of the platform
+func exeName(name string) string {
+	if runtime.GOOS == "windows" {
+		return name + ".exe"
+	}
+	return name
+}
@@ -1,12 +1,323 @@
 package golin
 
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
 const CompileSDK = "compile_sdk"
 
-//
+// 開発版SDKの作業用ディレクトリ名
+const (
+	compileSource = "." + CompileSDK + "_src"   //gitのチェックアウト
+	compileBuild  = "." + CompileSDK + "_build" //ビルド用のディレクトリ
+	compileOld    = "." + CompileSDK + "_old"   //入れ替え前のSDK(接頭辞、実行中の場合は残る為一意な名称にする)
+	revisionFile  = ".golin_revision"           //ビルドしたコミットの記録
+)
+
 // CompileGoSDK is Compile from the latest repository to Create GoSDK
 //
 // Create()にCompileSDKを渡すことで開発用のgotipの実行を行います
-//
 func CompileLatestSDK() error {
 	return Create(CompileSDK)
 }
+
+// readyDevelopment is incremental build of the development SDK
+//
+// gitのチェックアウトを残しておき、新しいコミットをfetchします
+// HEADが前回のビルドと変わっていた場合のみ別ディレクトリでビルドを行い、
+// 完了後にcompile_sdkと入れ替えます
+func (m *Manager) readyDevelopment(ctx context.Context, dir string) (string, error) {
+
+	path := filepath.Join(dir, CompileSDK)
+	src := filepath.Join(dir, compileSource)
+
+	//前回残した古いSDKを削除
+	m.removeOldSDK(dir)
+
+	rev, err := m.fetchSource(ctx, src)
+	if err != nil {
+		return "", xerrors.Errorf("fetch source: %w", err)
+	}
+
+	built := builtRevision(path)
+	if built == rev {
+		m.logger.Info("development SDK is up to date", "revision", rev, "path", path)
+		return path, nil
+	}
+
+	m.logger.Info("build development SDK", "revision", rev, "before", built)
+
+	build := filepath.Join(dir, compileBuild)
+	//失敗したビルドが残っている場合がある
+	err = os.RemoveAll(build)
+	if err != nil {
+		return "", xerrors.Errorf("remove build directory: %w", err)
+	}
+
+	err = m.exportSource(ctx, src, rev, build)
+	if err != nil {
+		os.RemoveAll(build)
+		return "", xerrors.Errorf("export source: %w", err)
+	}
+
+	start := time.Now()
+	err = m.makeSDK(ctx, build)
+	if err != nil {
+		os.RemoveAll(build)
+		return "", xerrors.Errorf("make SDK: %w", err)
+	}
+	m.logger.Info("built development SDK", "path", build, "duration", time.Since(start))
+
+	err = os.WriteFile(filepath.Join(build, revisionFile), []byte(rev+"\n"), 0666)
+	if err != nil {
+		os.RemoveAll(build)
+		return "", xerrors.Errorf("write revision: %w", err)
+	}
+
+	err = m.swapSDK(dir, build, path)
+	if err != nil {
+		return "", xerrors.Errorf("swap SDK: %w", err)
+	}
+	m.recordFiles(CompileSDK)
+
+	return path, nil
+}
+
+// fetchSource is git clone or fetch
+//
+// チェックアウトが存在しない場合はcloneし、存在する場合はfetchして
+// 最新のコミットに合わせます
+// 戻り値はHEADのコミットハッシュです
+func (m *Manager) fetchSource(ctx context.Context, src string) (string, error) {
+
+	_, err := os.Stat(filepath.Join(src, ".git"))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return "", xerrors.Errorf("os.Stat(): %w", err)
+		}
+		m.logger.Info("clone", "url", m.sourceRepository, "path", src)
+		cmd := command(ctx, "git", "clone", "--depth=1",
+			"--branch", m.sourceBranch, m.sourceRepository, src)
+		err = m.runCmd(ctx, cmd)
+		if err != nil {
+			os.RemoveAll(src)
+			return "", xerrors.Errorf("git clone: %w", err)
+		}
+	} else {
+		m.logger.Info("fetch", "url", m.sourceRepository, "path", src)
+		err = m.runGit(ctx, src, "fetch", "--depth=1", "origin", m.sourceBranch)
+		if err != nil {
+			return "", xerrors.Errorf("git fetch: %w", err)
+		}
+		err = m.runGit(ctx, src, "reset", "--hard", "FETCH_HEAD")
+		if err != nil {
+			return "", xerrors.Errorf("git reset: %w", err)
+		}
+	}
+
+	out, err := m.gitOutput(ctx, src, "rev-parse", "HEAD")
+	if err != nil {
+		return "", xerrors.Errorf("git rev-parse: %w", err)
+	}
+	return out, nil
+}
+
+// builtRevision is revision of the built SDK
+//
+// ビルド済みのSDKのコミットハッシュを返します
+// 存在しない場合は空文字を返します
+func builtRevision(path string) string {
+	b, err := os.ReadFile(filepath.Join(path, revisionFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// exportSource is git archive to build directory
+//
+// チェックアウトの指定コミットをビルド用のディレクトリに展開します
+// .gitが存在しない為、VERSIONファイルを作成してバージョンを決定します
+func (m *Manager) exportSource(ctx context.Context, src, rev, build string) error {
+
+	cmd := command(ctx, "git", "archive", "--format=tar.gz", "--prefix=go/", rev)
+	cmd.Dir = src
+	cmd.Stderr = m.stderr
+
+	r, err := cmd.StdoutPipe()
+	if err != nil {
+		return xerrors.Errorf("StdoutPipe(): %w", err)
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return xerrors.Errorf("git archive start: %w", err)
+	}
+
+	err = m.decompressTarGz(ctx, r, build, releasePrefix)
+	if err != nil {
+		cmd.Wait()
+		return xerrors.Errorf("decompressTarGz(): %w", err)
+	}
+
+	err = cmd.Wait()
+	if err != nil {
+		return xerrors.Errorf("git archive wait: %w", err)
+	}
+
+	ver, err := m.gitOutput(ctx, src, "log", "-1", "--format=devel +%h %cd", rev)
+	if err != nil {
+		return xerrors.Errorf("git log: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(build, "VERSION"), []byte(ver), 0666)
+	if err != nil {
+		return xerrors.Errorf("write VERSION: %w", err)
+	}
+	return nil
+}
+
+// makeSDK is run make.bash(make.bat)
+//
+// 現在のgoコマンドのGOROOTをブートストラップにしてビルドします
+func (m *Manager) makeSDK(ctx context.Context, build string) error {
+
+	bootstrap := GetGoEnv("GOROOT")
+	if bootstrap == "" {
+		return fmt.Errorf("bootstrap GOROOT not found.")
+	}
+
+	script := "./make.bash"
+	if runtime.GOOS == "windows" {
+		script = "make.bat"
+	}
+
+	cmd := command(ctx, script)
+	cmd.Dir = filepath.Join(build, "src")
+	cmd.Env = append(removeEnv(os.Environ(), "GOROOT"), "GOROOT_BOOTSTRAP="+bootstrap)
+
+	return m.runCmd(ctx, cmd)
+}
+
+// swapSDK is replace development SDK
+//
+// 既存のSDKを一意な名称で退避してからビルドしたSDKと入れ替えます
+// 実行中で削除できずに残った退避先があっても入れ替えられるようにする為です
+// 入れ替えに失敗した場合は退避したSDKを元に戻します
+func (m *Manager) swapSDK(dir, build, path string) error {
+
+	old := filepath.Join(dir, fmt.Sprintf("%s_%d", compileOld, time.Now().UnixNano()))
+	running := isRunningSDK(path)
+
+	_, err := os.Stat(path)
+	exists := err == nil
+	if exists {
+		err = os.Rename(path, old)
+		if err != nil {
+			return xerrors.Errorf("rename current SDK: %w", err)
+		}
+	}
+
+	err = os.Rename(build, path)
+	if err != nil {
+		if exists {
+			os.Rename(old, path)
+		}
+		return xerrors.Errorf("rename build SDK: %w", err)
+	}
+
+	if !exists {
+		return nil
+	}
+
+	//実行中のツールチェインは次回に削除
+	if running {
+		m.logger.Warn("previous SDK is kept because it is running", "path", old)
+		return nil
+	}
+
+	m.removeOldSDK(dir)
+	return nil
+}
+
+// removeOldSDK is remove previous development SDKs
+//
+// 退避したSDKのうち、実行中のツールチェインでないものを削除します
+// 削除できなかった場合は次回のビルド時に再度削除します
+func (m *Manager) removeOldSDK(dir string) {
+
+	list, err := filepath.Glob(filepath.Join(dir, compileOld+"*"))
+	if err != nil {
+		return
+	}
+
+	for _, old := range list {
+		if isRunningSDK(old) {
+			continue
+		}
+		err := os.RemoveAll(old)
+		if err != nil {
+			m.logger.Warn("remove previous SDK", "path", old, "error", err)
+		}
+	}
+}
+
+// isRunningSDK is running toolchain check
+//
+// 現在のgoコマンドのGOROOTが引数のパスと同一かを判定します
+func isRunningSDK(path string) bool {
+
+	goroot := GetGoEnv("GOROOT")
+	if goroot == "" {
+		return false
+	}
+
+	running, err := filepath.EvalSymlinks(goroot)
+	if err != nil {
+		return false
+	}
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	return running == target
+}
+
+// runGit is git command running in the directory
+func (m *Manager) runGit(ctx context.Context, dir string, args ...string) error {
+	cmd := command(ctx, "git", args...)
+	cmd.Dir = dir
+	return m.runCmd(ctx, cmd)
+}
+
+// gitOutput is git command output
+func (m *Manager) gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := command(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stderr = m.stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", xerrors.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// removeEnv is remove key from environment list
+func removeEnv(env []string, key string) []string {
+	rtn := make([]string, 0, len(env))
+	for _, elm := range env {
+		if strings.HasPrefix(elm, key+"=") {
+			continue
+		}
+		rtn = append(rtn, elm)
+	}
+	return rtn
+}
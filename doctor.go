@@ -0,0 +1,102 @@
+package golin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"golang.org/x/xerrors"
+)
+
+// DoctorResult is result of Manager.Doctor
+type DoctorResult struct {
+	Current *CurrentResult
+	Support SupportStatus //リンク先のマイナーバージョンのサポート状況
+	//サポート中のマイナーバージョン(新しい順、例: 1.22, 1.21)
+	SupportedMinors []string
+	Latest          *Version //同じマイナーバージョンの最新のリリース(不明な場合はnil)
+	ReleaseNotes    string   //リリースノート
+	ListError       error    //バージョンの一覧を取得できなかった場合のエラー
+	VulnDB          string   //脆弱性のデータベース(未指定の場合は空)
+	Vulns           []*Vuln  //リンク先のバージョンの既知の脆弱性
+}
+
+// Problems is number of the warnings
+//
+// サポート終了、既知の脆弱性、更新がある場合に数えます
+func (r *DoctorResult) Problems() int {
+	n := len(r.Vulns)
+	if r.Support == Unsupported {
+		n++
+	}
+	if r.Latest != nil && r.Current.Version.Less(r.Latest) {
+		n++
+	}
+	return n
+}
+
+//
+// Doctor is diagnose the linked SDK
+//
+// リンク先のバージョンのサポート状況、同じマイナーバージョンの更新、
+// 脆弱性のデータベース(SetVulnDB)がある場合は既知の脆弱性を確認します
+// バージョンの一覧を取得できない場合もエラーにせずListErrorに設定します
+//
+func (m *Manager) Doctor(ctx context.Context) (*DoctorResult, error) {
+
+	cur, err := m.Current(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("Current(): %w", err)
+	}
+
+	rtn := DoctorResult{
+		Current:      cur,
+		ReleaseNotes: cur.Version.ReleaseNotes(),
+		VulnDB:       m.vulnDB,
+	}
+
+	releases, err := m.fetchReleases(ctx)
+	if err != nil {
+		m.logger.Warn("fetch version list", "error", err)
+		rtn.ListError = err
+	} else {
+		versions := make([]*Version, len(releases))
+		for i, r := range releases {
+			versions[i] = r.version
+			if r.version.Compare(cur.Version) == 0 {
+				cur.Version.date = r.version.date
+			}
+			if r.version.mean == Major && minorOf(r.version) == minorOf(cur.Version) &&
+				(rtn.Latest == nil || rtn.Latest.Less(r.version)) {
+				rtn.Latest = r.version
+			}
+		}
+		minors := supportedMinors(versions)
+		setSupport(cur.Version, minors)
+		rtn.Support = cur.Version.support
+		for _, key := range minors {
+			rtn.SupportedMinors = append(rtn.SupportedMinors, fmt.Sprintf("%d.%d", key[0], key[1]))
+		}
+	}
+
+	if db := m.loadVulnDB(ctx); db != nil {
+		rtn.Vulns = db.Affecting(cur.Version)
+		sort.Slice(rtn.Vulns, func(i, j int) bool {
+			return rtn.Vulns[i].ID < rtn.Vulns[j].ID
+		})
+	}
+	return &rtn, nil
+}
+
+//
+// Vulnerabilities is known vulnerabilities of the version
+//
+// 脆弱性のデータベースの指定がない場合、読み込めない場合はfalseを返します
+//
+func (m *Manager) Vulnerabilities(ctx context.Context, v *Version) ([]*Vuln, bool) {
+	db := m.loadVulnDB(ctx)
+	if db == nil {
+		return nil, false
+	}
+	return db.Affecting(v), true
+}
@@ -0,0 +1,143 @@
+package golin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// エラーの種類
+//
+// golinが返すエラーはerrors.Is()で以下のいずれかと判定できます
+var (
+	ErrVersionNotFound  = errors.New("version does not exist")
+	ErrPermission       = errors.New("permission denied")
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	ErrNetwork          = errors.New("network unavailable")
+	ErrCancelled        = errors.New("user cancelled")
+	ErrAlreadyCurrent   = errors.New("already current")
+)
+
+// ErrAlreadyInstalled is the SDK already exists in the root
+//
+// Manager.ImportSDK()が返します
+// 切り替えの対象ではない為、ErrAlreadyCurrentとは区別します(終了コードはExitError)
+var ErrAlreadyInstalled = errors.New("already installed")
+
+// errNoRoot is root not found
+var errNoRoot = errors.New("golin root is not found (set GOROOT or -goroot).")
+
+// Error is classified error
+//
+// Kindにエラーの種類、Errに原因のエラーを持ちます
+// errors.Is()はKind、Errの両方に対して判定を行います
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Kind.Error()
+	}
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// ChecksumError is checksum mismatch
+//
+// errors.Is(err, ErrChecksumMismatch)がtrueになります
+type ChecksumError struct {
+	URL      string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch: %s expected %s, got %s", e.URL, e.Expected, e.Actual)
+}
+
+func (e *ChecksumError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+// classify is wrap the error with the kind
+func classify(kind, err error) error {
+	return &Error{Kind: kind, Err: err}
+}
+
+// classifyRequest is classify HTTP request error
+//
+// キャンセルの場合はErrCancelled、それ以外はErrNetworkとします
+func classifyRequest(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return classify(ErrCancelled, err)
+	}
+	return classify(ErrNetwork, err)
+}
+
+// classifyPermission is classify file system error
+//
+// 権限のエラーの場合はErrPermissionとします
+func classifyPermission(err error) error {
+	if errors.Is(err, fs.ErrPermission) {
+		return classify(ErrPermission, err)
+	}
+	return err
+}
+
+// classifyContext is classify cancellation
+//
+// コンテキストが終了している場合はErrCancelledとします
+func classifyContext(ctx context.Context, err error) error {
+	if ctx.Err() != nil && !errors.Is(err, ErrCancelled) {
+		return classify(ErrCancelled, err)
+	}
+	return err
+}
+
+// プロセスの終了コード
+//
+// ExitCode()でエラーの種類ごとに以下の終了コードを返します
+const (
+	ExitOK              = 0   //正常終了
+	ExitError           = 1   //分類されないエラー
+	ExitUsage           = 2   //引数の誤り
+	ExitVersionNotFound = 3   //ErrVersionNotFound
+	ExitPermission      = 4   //ErrPermission
+	ExitChecksum        = 5   //ErrChecksumMismatch
+	ExitNetwork         = 6   //ErrNetwork
+	ExitAlreadyCurrent  = 7   //ErrAlreadyCurrent
+	ExitCancelled       = 130 //ErrCancelled(SIGINTと同じ)
+)
+
+// ExitCode is process exit code of the error
+//
+// エラーの種類から終了コードを返します
+// 複数に該当する場合はキャンセルを優先します
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrCancelled), errors.Is(err, context.Canceled):
+		return ExitCancelled
+	case errors.Is(err, ErrVersionNotFound):
+		return ExitVersionNotFound
+	case errors.Is(err, ErrPermission):
+		return ExitPermission
+	case errors.Is(err, ErrChecksumMismatch):
+		return ExitChecksum
+	case errors.Is(err, ErrNetwork):
+		return ExitNetwork
+	case errors.Is(err, ErrAlreadyCurrent):
+		return ExitAlreadyCurrent
+	}
+	return ExitError
+}
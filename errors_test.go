@@ -0,0 +1,138 @@
+package golin_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shizuokago/golin/v2"
+	"github.com/shizuokago/golin/v2/internal/golintest"
+)
+
+func TestInstallErrors(t *testing.T) {
+
+	ctx := context.Background()
+
+	serv := golintest.NewServer(t, "1.99.0")
+
+	root := t.TempDir()
+	m := serv.NewManager(t, root)
+
+	_, err := m.Install(ctx, "1.98.0")
+	if !errors.Is(err, golin.ErrVersionNotFound) {
+		t.Errorf("Install not found error[%v]", err)
+	}
+	if golin.ExitCode(err) != golin.ExitVersionNotFound {
+		t.Errorf("ExitCode [%d]", golin.ExitCode(err))
+	}
+
+	rtn, err := m.Install(ctx, "1.99.0")
+	if err != nil {
+		t.Fatalf("Install error[%v]", err)
+	}
+	if rtn.Path != filepath.Join(root, "1.99.0") {
+		t.Errorf("Install path [%s]", rtn.Path)
+	}
+
+	_, err = m.Switch(ctx, "1.99.0")
+	if !errors.Is(err, golin.ErrAlreadyCurrent) {
+		t.Errorf("Switch already current error[%v]", err)
+	}
+	if golin.ExitCode(err) != golin.ExitAlreadyCurrent {
+		t.Errorf("ExitCode [%d]", golin.ExitCode(err))
+	}
+}
+
+func TestInstallChecksum(t *testing.T) {
+
+	serv := golintest.NewServer(t, "1.99.0")
+	serv.SetChecksum("1.99.0", strings.Repeat("0", 64))
+
+	//検証前に展開していないことをログで確認する
+	var log bytes.Buffer
+	root := t.TempDir()
+	m := serv.NewManager(t, root, golin.SetLogger(slog.New(slog.NewTextHandler(&log, nil))))
+
+	_, err := m.Install(context.Background(), "1.99.0")
+	if !errors.Is(err, golin.ErrChecksumMismatch) {
+		t.Fatalf("Install checksum error[%v]", err)
+	}
+
+	var cerr *golin.ChecksumError
+	if !errors.As(err, &cerr) {
+		t.Errorf("ChecksumError not found[%v]", err)
+	} else if cerr.Expected != strings.Repeat("0", 64) {
+		t.Errorf("ChecksumError expected[%s]", cerr.Expected)
+	}
+
+	if golin.ExitCode(err) != golin.ExitChecksum {
+		t.Errorf("ExitCode [%d]", golin.ExitCode(err))
+	}
+
+	if strings.Contains(log.String(), "msg=decompress ") {
+		t.Errorf("decompressed before the checksum verification")
+	}
+	if _, err := os.Stat(filepath.Join(root, "1.99.0")); !os.IsNotExist(err) {
+		t.Errorf("mismatch directory exists[%v]", err)
+	}
+}
+
+func TestInstallChecksumMissing(t *testing.T) {
+
+	serv := golintest.NewServer(t, "1.99.0")
+	serv.SetChecksum("1.99.0", "")
+
+	root := t.TempDir()
+	m := serv.NewManager(t, root)
+
+	ctx := context.Background()
+	_, err := m.Install(ctx, "1.99.0")
+	if !errors.Is(err, golin.ErrChecksumMismatch) {
+		t.Fatalf("Install not published error[%v]", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "1.99.0")); !os.IsNotExist(err) {
+		t.Errorf("not published directory exists[%v]", err)
+	}
+
+	//明示的に許可した場合のみ検証せずに展開する
+	m = serv.NewManager(t, root, golin.SetAllowMissingChecksum(true))
+	_, err = m.Install(ctx, "1.99.0")
+	if err != nil {
+		t.Fatalf("Install allow missing error[%v]", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "1.99.0", "VERSION")); err != nil {
+		t.Errorf("Install allow missing [%v]", err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{nil, golin.ExitOK},
+		{errors.New("other"), golin.ExitError},
+		{golin.ErrVersionNotFound, golin.ExitVersionNotFound},
+		{golin.ErrPermission, golin.ExitPermission},
+		{golin.ErrChecksumMismatch, golin.ExitChecksum},
+		{golin.ErrNetwork, golin.ExitNetwork},
+		{golin.ErrAlreadyCurrent, golin.ExitAlreadyCurrent},
+		{golin.ErrCancelled, golin.ExitCancelled},
+		{context.Canceled, golin.ExitCancelled},
+		{fmt.Errorf("wrap: %w", &golin.Error{Kind: golin.ErrNetwork, Err: context.Canceled}), golin.ExitCancelled},
+		{&golin.ChecksumError{}, golin.ExitChecksum},
+	}
+
+	for _, test := range tests {
+		if code := golin.ExitCode(test.err); code != test.code {
+			t.Errorf("ExitCode(%v) [%d] != [%d]", test.err, code, test.code)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package golin
+
+// CopyDir is copyDir for the tests
+//
+// 別のファイルシステムへの移動はテストで再現できない為、コピーを直接確認します
+func (m *Manager) CopyDir(src, dst string) error {
+	return m.copyDir(src, dst)
+}
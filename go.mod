@@ -1,10 +1,20 @@
 module github.com/shizuokago/golin/v2
 
-go 1.12
+go 1.24
 
 require (
 	github.com/PuerkitoBio/goquery v1.6.0
 	github.com/cheggaaa/pb/v3 v3.0.5
-	golang.org/dl v0.0.0-20210713194856-38ddc79c2163 // indirect
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1
 )
+
+require (
+	github.com/VividCortex/ewma v1.1.1 // indirect
+	github.com/andybalholm/cascadia v1.1.0 // indirect
+	github.com/fatih/color v1.7.0 // indirect
+	github.com/mattn/go-colorable v0.1.2 // indirect
+	github.com/mattn/go-isatty v0.0.12 // indirect
+	github.com/mattn/go-runewidth v0.0.7 // indirect
+	golang.org/x/net v0.0.0-20200202094626-16171245cfb2 // indirect
+	golang.org/x/sys v0.0.0-20200116001909-b77594299b42 // indirect
+)
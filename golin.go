@@ -1,14 +1,21 @@
 package golin
 
 import (
-	"bufio"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/shizuokago/golin/v2/config"
+	"github.com/shizuokago/golin/v2/i18n"
 	"golang.org/x/xerrors"
 )
 
@@ -17,81 +24,25 @@ const (
 	workDirectory = "golin_work" //権限確認用のディレクトリ名
 )
 
-var currentVersion *Version
-
 //
 // checkAuthorization is authorization check
 //
-// 引数のパスにリンクが貼れるかをワークでチェック
+// 引数のパスにSDKを展開できるかをワークでチェック
+// SDKの展開は権限を昇格せずに行う為、書き込めない場合はエラーとします
+// (リンクの作成はcreateLink()で必要な場合のみ昇格します)
 //
 func checkAuthorization(path string) error {
 
 	work := filepath.Join(path, "."+workDirectory)
 	err := os.Mkdir(work, 0777)
 	if err != nil {
-		return xerrors.Errorf("そのディレクトリに権限がありません。: %w", err)
+		return classify(ErrPermission, xerrors.Errorf("cannot create a directory in %s: %w", path, err))
 	}
 	defer os.Remove(work)
 
-	link := filepath.Join(path, "_"+workDirectory+"_")
-	err = os.Symlink(work, link)
-	if err != nil {
-		return xerrors.Errorf("シンボリックリンクの作成に失敗しました。", err)
-	}
-	defer os.Remove(link)
-
 	return nil
 }
 
-//
-// Function getRoot is return Work Directory Path
-//
-// この関数は処理対象のディレクトリを返します。
-// 具体的には現在のGOROOTの上の階層を返します。
-// GOROOTが存在しない場合はエラーとなります
-//
-func getRoot(ver string) (string, error) {
-
-	goroot := os.Getenv("GOROOT")
-	if goroot == "" {
-		return "", fmt.Errorf("golin command required GOROOT environment variable.")
-	}
-
-	conf := config.Get()
-	link := conf.LinkName
-
-	root := filepath.Dir(goroot)
-	now := filepath.Base(goroot)
-	idx := strings.Index(goroot, link)
-
-	//最後がリンク名と同一かを見る
-	if idx != len(goroot)-len(link) {
-		fmt.Fprintf(os.Stdout, `
-This command creates the Go SDK within the current GOROOT parent directory. 
-It is recommended to specify a dedicated directory.
-
-%s -
-   |- %s
-   |- %s [Download specified Go SDK]
-   |- %s <- symbolic link that the creates.(Eval:%s)
-
-By changing the environment variable GOROOT to [%s], you can easily switch GOROOT.
-
-Is it OK?[Y/n] 
-`, root, now, ver, link, ver, filepath.Join(root, link))
-
-		//入力受付
-		stdin := bufio.NewScanner(os.Stdin)
-		stdin.Scan()
-		text := stdin.Text()
-		if text != "Y" {
-			return "", fmt.Errorf("Cancel.")
-		}
-	}
-
-	return root, nil
-}
-
 //
 // GetGoPath is return GOPATH
 //
@@ -125,29 +76,34 @@ func getSDKPath(v string) string {
 // そのままGOPATHの位置でinstallされ、コマンドが作成されますので
 // そのコマンド名も返します
 //
-func createDownloadCmd(v string) (string, error) {
+func (m *Manager) createDownloadCmd(ctx context.Context, v string) (string, error) {
 
 	link := fmt.Sprintf("%s/go%s", config.GoGetLink, v)
 	sub := "get"
 
-	if currentVersion != nil &&
-		currentVersion.Compare(NewVersion("1.16")) >= 0 {
+	if m.goVersion != nil &&
+		m.goVersion.Compare(NewVersion("1.16")) >= 0 {
 		sub = "install"
 		link += "@latest"
 	}
 
 	// go get golang.org/dl/go{version}
-	cmd := exec.Command("go", sub, link)
-	err := runCmd(cmd)
+	m.logger.Info("install download command", "package", link)
+	cmd := command(ctx, "go", sub, link)
+	var out bytes.Buffer
+	cmd.Stderr = io.MultiWriter(m.stderr, &out)
+	err := m.runCmd(ctx, cmd)
 	if err != nil {
+		//golang.org/dlにバージョンが存在しない
+		if strings.Contains(out.String(), "does not contain package") ||
+			strings.Contains(out.String(), "cannot find package") {
+			return "", classify(ErrVersionNotFound, err)
+		}
 		return "", err
 	}
 
-	//GOEXE windows excutable file extention
-	// go{version}{.exe}
-	genCmd := fmt.Sprintf("go%s%s", v, GetGoEnv("GOEXE"))
-	genPath := filepath.Join(GetGoPath(), "bin", genCmd)
-	return genPath, nil
+	// $GOBIN or $GOPATH/bin/go{version}{.exe}
+	return wrapperPath(v), nil
 }
 
 //
@@ -157,48 +113,115 @@ func createDownloadCmd(v string) (string, error) {
 // 戻り値はダウンロードして来たディレクトリを返します
 //
 func Download(v string) (string, error) {
+	m, err := defaultManager()
+	if err != nil {
+		return "", xerrors.Errorf("defaultManager(): %w", err)
+	}
+	return m.Download(context.Background(), v)
+}
+
+//
+// Download is Go download
+//
+// golang.org/dl/goX.x.x を利用してGo言語をダウンロードします
+// 戻り値はダウンロードして来たディレクトリを返します
+//
+func (m *Manager) Download(ctx context.Context, v string) (string, error) {
+
+	if m.goVersion == nil {
+		m.goVersion = m.goCommandVersion()
+	}
+
+	//作成したファイルをマニフェストに記録する為、ダウンロード前の状態を確認
+	wrapper := wrapperPath(v)
+	_, err := os.Lstat(wrapper)
+	wrapperExists := wrapper != "" && err == nil
+	sdk := getSDKPath(v)
+	_, err = os.Stat(sdk)
+	exists := err == nil
+	modules := dlModules()
 
 	//$GOPATH/bin/go{version}{.exe}
-	bin, err := createDownloadCmd(v)
+	bin, err := m.createDownloadCmd(ctx, v)
+	//失敗した場合も残ったファイルはgolin cleanで削除できるように記録
+	m.trackArtifacts(downloadArtifacts(v, wrapper, wrapperExists, exists, modules)...)
 	if err != nil {
 		return "", xerrors.Errorf("create download command: %w", err)
 	}
-	//delete exe file
-	defer os.Remove(bin)
+	//delete exe file(golinが作成した場合のみ)
+	if !wrapperExists {
+		defer func() {
+			if err := os.Remove(bin); err == nil || errors.Is(err, fs.ErrNotExist) {
+				m.untrackArtifacts(bin)
+			}
+		}()
+	}
+
+	start := time.Now()
+	m.logger.Info("download", "version", v, "command", bin, "path", sdk)
 
-	err = runDownloadCmd(bin)
+	err = m.runDownloadCmd(ctx, bin)
 	if err != nil {
+		//キャンセル時は作成途中のSDKを削除
+		if !exists && ctx.Err() != nil && sdk != "" {
+			os.RemoveAll(sdk)
+		}
 		return "", xerrors.Errorf("run download command: %w", err)
 	}
 
+	m.logger.Info("downloaded", "version", v, "path", sdk, "duration", time.Since(start))
+
 	return getSDKPath(v), nil
 }
 
 //
-// readyLink is remove symbolic link
+// readyLink is move the symbolic link aside
 //
 // シンボリックリンクは存在する場合の
-// コマンドの動作が違うので削除を行っておきます
-// 現状初回起動時にシンボリックリンクがない場合に
-// 問い合わせしたりする処理がありません
+// コマンドの動作が違うので退避を行っておきます
+// 退避したリンク(ない場合は空)を返し、新しいリンクの作成に失敗した場合は
+// restoreLink()で元に戻します
+// リンク以外のファイルは削除します
 //
-// BUG(secondarykey): 作成に失敗した場合のロールバックがない
-//
-func readyLink(dir string) (string, error) {
+func (m *Manager) readyLink() (string, string, error) {
 
-	conf := config.Get()
+	link := m.linkPath()
+	info, err := os.Lstat(link)
+	if err != nil {
+		return link, "", nil
+	}
 
-	link := filepath.Join(dir, conf.LinkName)
-	//symbliclink
-	if _, err := os.Lstat(link); err == nil {
+	if info.Mode()&os.ModeSymlink == 0 {
 		err = os.Remove(link)
 		if err != nil {
-			return "", err
+			return "", "", err
 		}
-	} else {
-		//???
+		return link, "", nil
+	}
+
+	old := link + linkSuffix
+	os.Remove(old)
+	err = os.Rename(link, old)
+	if err != nil {
+		return "", "", err
+	}
+	return link, old, nil
+}
+
+//
+// restoreLink is restore the symbolic link moved aside by readyLink
+//
+// 作成途中のリンクが存在する場合は削除してから戻します
+//
+func (m *Manager) restoreLink(link, old string) {
+	if old == "" {
+		return
+	}
+	os.Remove(link)
+	err := os.Rename(old, link)
+	if err != nil {
+		m.logger.Warn("restore link", "path", link, "error", err)
 	}
-	return link, nil
 }
 
 //
@@ -208,36 +231,74 @@ func readyLink(dir string) (string, error) {
 // 存在しない場合はダウンロードを行って準備する
 // 存在するバージョンの場合はそのままパスを返す
 //
-func readyPath(dir, v string) (string, error) {
+func (m *Manager) readyPath(ctx context.Context, dir, v string) (string, error) {
+
+	//開発版は差分ビルド
+	if v == CompileSDK {
+		path, err := m.readyDevelopment(ctx, dir)
+		if err != nil {
+			return "", err
+		}
+		m.chown(path)
+		m.recordInstall(CompileSDK, &ManifestEntry{
+			Version: CompileSDK,
+			URL:     m.sourceRepository + "@" + builtRevision(path),
+			Method:  MethodSource,
+		})
+		return path, nil
+	}
 
 	path := filepath.Join(dir, v)
 	_, err := os.Stat(path)
 	//Exist
 	if err == nil {
-		if v == CompileSDK {
-			err := os.RemoveAll(path)
-			if err != nil {
-				//開発中実行がこのパスだった場合goを削除できないので無視
-				fmt.Fprintln(os.Stderr, err)
-			}
-			//再度ダウンロード用に設定する
-			v = "tip"
-		} else {
-			return path, nil
+		m.logger.Debug("version exists", "path", path)
+		//壊れている場合は警告のみ(golin verifyで確認、修復できる)
+		if reason := validateSDK(path, v); reason != "" {
+			m.logger.Warn("installed SDK may be damaged, run golin verify", "path", path, "reason", reason)
+		}
+		return path, nil
+	}
+
+	//goコマンドがダウンロード済みの場合はモジュールキャッシュから取り込む
+	if tc := m.findToolchain(ctx, v); tc != nil {
+		err = m.importToolchain(dir, tc)
+		if err != nil {
+			return "", xerrors.Errorf("importToolchain() error: %w", err)
+		}
+		return path, nil
+	}
+
+	//アーカイブからインストール
+	if m.source.Archive {
+		_, _, err = m.installArchive(ctx, dir, NewVersion(v))
+		if err != nil {
+			return "", xerrors.Errorf("installArchive() error: %w", err)
 		}
+		return path, nil
 	}
 
 	//go download
-	sdk, err := Download(v)
+	sdk, err := m.Download(ctx, v)
 	if err != nil {
 		return "", xerrors.Errorf("download error: %w", err)
 	}
 
 	//Download SDK Rename
-	err = os.Rename(sdk+string(filepath.Separator), path+string(filepath.Separator))
+	m.logger.Info("move SDK", "from", sdk, "to", path)
+	err = m.moveDir(sdk, path)
 	if err != nil {
-		return "", xerrors.Errorf("rename error: %w", err)
+		return "", xerrors.Errorf("move error: %w", err)
 	}
+	m.untrackArtifacts(sdk)
+	m.chown(path)
+
+	m.recordFiles(v)
+	m.recordInstall(v, &ManifestEntry{
+		Version: v,
+		URL:     fmt.Sprintf("%s/go%s", config.GoGetLink, v),
+		Method:  MethodDownload,
+	})
 	return path, nil
 }
 
@@ -247,27 +308,37 @@ func readyPath(dir, v string) (string, error) {
 // 実際コマンドを実行する処理
 // 標準出力等を一括管理する為に関数化を行った
 //
-func runCmd(cmd *exec.Cmd) error {
+func (m *Manager) runCmd(ctx context.Context, cmd *exec.Cmd) error {
 
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	if cmd.Stdout == nil {
+		cmd.Stdout = m.stdout
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = m.stderr
+	}
 
+	m.logger.Debug("run", "command", cmd.Args, "dir", cmd.Dir)
 	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return classify(ErrCancelled, xerrors.Errorf("runCmd() canceled: %w", ctx.Err()))
+		}
 		return xerrors.Errorf("runCmd() error: %w", err)
 	}
 	return nil
 }
 
-func runDownloadCmd(bin string) error {
-
-	//fmt.Println("download cmd start")
+func (m *Manager) runDownloadCmd(ctx context.Context, bin string) error {
 
 	// $GOPATH/bin/go{version}{.exe} download
-	cmd := exec.Command(bin, "download")
-	w := &downloadWriter{}
+	cmd := command(ctx, bin, "download")
+	w := &downloadWriter{
+		out:      m.stdout,
+		err:      m.stderr,
+		logger:   m.logger,
+		progress: m.progress,
+	}
 	cmd.Stdout = w
 	cmd.Stderr = w
-	cmd.Stdin = os.Stdin
 
 	err := cmd.Start()
 	if err != nil {
@@ -276,14 +347,42 @@ func runDownloadCmd(bin string) error {
 
 	err = cmd.Wait()
 	if err != nil {
+		if ctx.Err() != nil {
+			return classify(ErrCancelled, xerrors.Errorf("command canceled: %w", ctx.Err()))
+		}
+		//リリースが存在しないバージョン
+		if w.notFound {
+			return classify(ErrVersionNotFound, err)
+		}
 		return xerrors.Errorf("command wait error: %w", err)
 	}
 
-	//fmt.Println("download cmd end")
 	return nil
 }
 
+//
+// command is cancelable command
+//
+// コンテキストがキャンセルされた場合、子プロセスも含めて終了させます
+//
+func command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	return cmd
+}
+
+//
+// downloadWriter is download command output
+//
+// ダウンロードの進捗は同じ行に上書きして表示します
+// 進捗表示が無効な場合は進捗をログに出力します
+//
 type downloadWriter struct {
+	out      io.Writer
+	err      io.Writer
+	logger   *slog.Logger
+	progress bool
+	notFound bool
 }
 
 func (w *downloadWriter) Write(b []byte) (int, error) {
@@ -293,11 +392,23 @@ func (w *downloadWriter) Write(b []byte) (int, error) {
 		if strings.Index(line, "\n") != -1 {
 			line = line[0 : len(line)-1]
 		}
-		fmt.Fprint(os.Stdout, "\r"+line)
+		if w.progress {
+			fmt.Fprint(w.out, "\r"+line)
+		} else {
+			w.logger.Debug(strings.TrimSpace(line))
+		}
 	} else if strings.Index(line, "Unpacking") != -1 {
-		fmt.Fprint(os.Stdout, "\n"+line)
+		if w.progress {
+			fmt.Fprint(w.out, "\n"+line)
+		} else {
+			w.logger.Info(strings.TrimSpace(line))
+		}
 	} else {
-		fmt.Fprint(os.Stderr, line)
+		if strings.Contains(line, "no binary release") ||
+			strings.Contains(line, "unknown version") {
+			w.notFound = true
+		}
+		fmt.Fprint(w.err, line)
 	}
 	return len(b), nil
 }
@@ -307,22 +418,20 @@ func (w *downloadWriter) Write(b []byte) (int, error) {
 //
 // go envを引数で実行します
 //
-// TODO(secondarykey) : change replaceall(1.12 after,,,)
-//
 func GetGoEnv(key string) string {
 	out, err := exec.Command("go", "env", key).Output()
 	if err != nil {
 		return ""
 	}
-	return strings.Replace(string(out), "\n", "", -1)
+	return strings.ReplaceAll(string(out), "\n", "")
 }
 
 //
 // printGoVersion is current go command version
 //
-// go versionを実行します
+// go versionを実行し、結果を表示します
 //
-func printGoVersion(prefix string) *Version {
+func (m *Manager) printGoVersion(prefix string) *Version {
 
 	out, err := exec.Command("go", "version").Output()
 	if err != nil {
@@ -331,7 +440,30 @@ func printGoVersion(prefix string) *Version {
 
 	ver := strings.Replace(string(out), "\n", "", -1)
 
-	fmt.Fprintln(os.Stdout, prefix, ver)
+	fmt.Fprintln(m.stdout, prefix, ver)
+
+	return parseGoVersion(ver)
+}
+
+//
+// goCommandVersion is current go command version
+//
+// 表示を行わずにgo versionの結果を返します
+//
+func (m *Manager) goCommandVersion() *Version {
+	out, err := exec.Command("go", "version").Output()
+	if err != nil {
+		return nil
+	}
+	return parseGoVersion(strings.Replace(string(out), "\n", "", -1))
+}
+
+//
+// parseGoVersion is parse go version output
+//
+// go versionの出力からバージョンを取り出します
+//
+func parseGoVersion(ver string) *Version {
 
 	//go version go1.17beta1 windows/amd64
 	sl := strings.Split(ver, " ")
@@ -364,32 +496,11 @@ func existGo() bool {
 	return false
 }
 
-func printSetting(root, version string) {
-	fmt.Printf(`
-%s にGoの最新バージョン(%s)をインストールしました。
-環境変数GOROOTに%sを設定し、PATHをGOROOT/binに設定してください。
-
-今後は
-
-  $ golin 1.16
-
-などでバージョンの切り替えが可能になります。
-    `, root, version, root)
-}
-
-// リリース用のZIPを作成
-func CompressReleaseZip(dst string, cmd string) error {
-
-	w, err := os.Create(dst)
-	if err != nil {
-		return xerrors.Errorf("os.Create(): %w", err)
-	}
-	defer w.Close()
-
-	err = Compress(w, cmd, "README.md")
-	if err != nil {
-		return xerrors.Errorf("CompressZip(): %w", err)
-	}
-
-	return nil
+//
+// printSetting is setting after install
+//
+// インストール後の設定手順を表示します
+//
+func (m *Manager) printSetting(root, version string) {
+	fmt.Fprint(m.stdout, m.msg.Sprintf(i18n.Setting, root, version))
 }
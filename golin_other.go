@@ -1,11 +1,72 @@
-// +build !windows
+//go:build !windows
 
 package golin
 
 import (
 	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"syscall"
 )
 
+// dataHome is user data directory ($XDG_DATA_HOME or ~/.local/share)
+func dataHome() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	home := getHome()
+	if home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".local", "share")
+}
+
+//
+// lookupSudoUser is user who invoked sudo
+//
+// rootで実行され、環境変数SUDO_USER、SUDO_UID、SUDO_GIDがある場合のみ返します
+//
+func lookupSudoUser() *sudoUser {
+
+	name := os.Getenv("SUDO_USER")
+	if os.Geteuid() != 0 || name == "" || name == "root" {
+		return nil
+	}
+	uid, err := strconv.Atoi(os.Getenv("SUDO_UID"))
+	if err != nil {
+		return nil
+	}
+	gid, err := strconv.Atoi(os.Getenv("SUDO_GID"))
+	if err != nil {
+		return nil
+	}
+
+	u := sudoUser{name: name, uid: uid, gid: gid}
+	if usr, err := user.LookupId(strconv.Itoa(uid)); err == nil {
+		u.home = usr.HomeDir
+	}
+	if u.home == "" {
+		return nil
+	}
+	return &u
+}
+
+// fileOwner is uid of the file
+func fileOwner(info os.FileInfo) (int, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return int(st.Uid), true
+}
+
+// isCrossDeviceErrno is rename error between filesystems (EXDEV)
+func isCrossDeviceErrno(err error) bool {
+	return err == syscall.EXDEV
+}
+
 //
 // Function getHome is HOME directory
 //
@@ -19,3 +80,25 @@ func getHome() string {
 func getDownloadExt() string {
 	return "tar.gz"
 }
+
+//
+// setProcessGroup is kill the process group on cancel
+//
+// 子プロセスを別のプロセスグループで起動し、
+// キャンセル時にはグループごと終了させます
+//
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+//
+// replaceFile is replace dst with src
+//
+// 実行中のファイルでもrenameで置き換えることができます
+//
+func replaceFile(src, dst string) error {
+	return os.Rename(src, dst)
+}
@@ -1,47 +1,31 @@
 package golin_test
 
 import (
+	"archive/tar"
+	"archive/zip"
 	"bytes"
+	"compress/gzip"
+	"context"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/shizuokago/golin/v2"
+	"github.com/shizuokago/golin/v2/internal/golintest"
 )
 
 func init() {
 	rand.Seed(time.Now().UnixNano())
 }
 
-const testDir = "test_GOROOT"
-
-var workROOT string
-
-func TestMain(m *testing.M) {
-
-	work := filepath.Join(getHome(), testDir)
-	err := os.MkdirAll(work, 0777)
-	if err == nil {
-		workROOT = filepath.Join(work, "fake")
-
-		ret := m.Run()
-
-		err = os.RemoveAll(work)
-		if err != nil {
-			fmt.Printf("remove directory error[%v]\n", testDir)
-		}
-		os.Exit(ret)
-	} else {
-		fmt.Printf("make directory error[%v]\n", testDir)
-		os.Exit(1)
-	}
-}
-
 func TestGoEnv(t *testing.T) {
 
 	goexe := golin.GetGoEnv("GOEXE")
@@ -85,101 +69,331 @@ func TestGoPath(t *testing.T) {
 	}
 }
 
-func TestDownload(t *testing.T) {
+// isolateGoEnv is isolate the go command environment of the test
+//
+// ホストのモジュールキャッシュのツールチェイン(golang.org/toolchain)を
+// 取り込まないように、空のモジュールキャッシュを設定します
+func isolateGoEnv(t *testing.T) {
+	t.Setenv("GOMODCACHE", t.TempDir())
+	t.Setenv("GOENV", "off")
+	t.Setenv("GOFLAGS", "")
+	t.Setenv("GOTOOLCHAIN", "local")
+}
+
+func TestLifecycle(t *testing.T) {
+
+	isolateGoEnv(t)
+	serv := golintest.NewServer(t, "1.20.1", "1.21.0")
+	root := t.TempDir()
+	m := serv.NewManager(t, root)
 
-	if testing.Short() {
-		t.Skip("skipping Download() test in short mode.")
+	ctx := context.Background()
+	list, err := m.List(ctx)
+	if err != nil {
+		t.Fatalf("List error[%v]", err)
+	}
+	if len(list) != 2 || list[0].Installed || !list[1].Available {
+		t.Errorf("List before install %v", list)
 	}
 
-	sdk, err := golin.Download("1.12")
+	_, err = m.Install(ctx, "1.20.1")
 	if err != nil {
-		t.Errorf("Download error[%v]", err)
+		t.Fatalf("Install error[%v]", err)
 	}
 
-	defer func(path string) {
-		os.RemoveAll(path)
-	}(sdk)
+	//インストールしていないバージョンはアーカイブから作成
+	rtn, err := m.Switch(ctx, "1.21.0")
+	if err != nil {
+		t.Fatalf("Switch(create) error[%v]", err)
+	}
+	if rtn.Path != filepath.Join(root, "1.21.0") {
+		t.Errorf("Switch path [%s]", rtn.Path)
+	}
+
+	if runtime.GOOS != "windows" {
+		bin, err := m.Which(ctx, "", "go")
+		if err != nil {
+			t.Fatalf("Which error[%v]", err)
+		}
+		out, err := exec.Command(bin, "version").Output()
+		if err != nil || !strings.Contains(string(out), "go1.21.0") {
+			t.Errorf("go version [%s] error[%v]", out, err)
+		}
+	}
 
-	sdk, err = golin.Download("1.12")
+	//reswitch
+	_, err = m.Switch(ctx, "1.20.1")
 	if err != nil {
-		t.Errorf("Downloaded version error[%v]", err)
+		t.Errorf("Switch(exist) error[%v]", err)
 	}
 
-	//work -> GOROOT
+	list, err = m.List(ctx)
+	if err != nil {
+		t.Fatalf("List error[%v]", err)
+	}
+	if len(list) != 2 || !list[0].Installed || !list[0].Current || !list[1].Installed {
+		t.Errorf("List after switch %v", list)
+	}
 
-	sdk, err = golin.Download("1.7")
-	if err == nil {
-		t.Errorf("not download version [1.7](%s)", sdk)
+	_, err = m.Remove(ctx, "1.21.0")
+	if err != nil {
+		t.Errorf("Remove error[%v]", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "1.21.0")); !os.IsNotExist(err) {
+		t.Errorf("Remove directory exists[%v]", err)
 	}
 }
 
 func TestCreate(t *testing.T) {
 
-	if testing.Short() {
-		t.Skip("skipping Create() test in short mode.")
+	isolateGoEnv(t)
+	serv := golintest.NewServer(t, "1.20.1")
+	root := t.TempDir()
+	opts := []golin.Option{
+		golin.SetSource(serv.Source()),
+		golin.SetOutput(io.Discard, io.Discard),
 	}
 
-	sdk, err := golin.Download("1.12")
+	//GOROOTがない場合はユーザのルート
+	data := t.TempDir()
+	t.Setenv("GOROOT", "")
+	t.Setenv("XDG_DATA_HOME", data)
+	t.Setenv("LOCALAPPDATA", data)
+	m, err := golin.NewManager(opts...)
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+	if m.Root() != filepath.Join(data, "golin") {
+		t.Errorf("default root [%s]", m.Root())
+	}
+	_, err = m.Switch(context.Background(), "1.20.1")
 	if err != nil {
-		t.Errorf("Downloaded version error[%v]", err)
+		t.Errorf("Switch user root[%v]", err)
+	}
+	if _, err := os.Stat(filepath.Join(data, "golin", "1.20.1", "VERSION")); err != nil {
+		t.Errorf("user root directory[%v]", err)
 	}
 
-	//fakeの位置に移動
-	err = os.Rename(sdk+string(filepath.Separator), workROOT+string(filepath.Separator))
+	//GOROOTの上の階層をルートにする
+	t.Setenv("GOROOT", filepath.Join(root, "current"))
+	m, err = golin.NewManager(opts...)
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+	_, err = m.Switch(context.Background(), "1.20.1")
 	if err != nil {
-		t.Logf("Rename Error[%v]", err)
+		t.Errorf("Create 1.20.1[%v]", err)
 	}
+	if _, err := os.Stat(filepath.Join(root, "1.20.1", "VERSION")); err != nil {
+		t.Errorf("Create directory[%v]", err)
+	}
+}
 
-	org := os.Getenv("GOROOT")
-	defer func(path string) {
-		err := os.Setenv("GOROOT", path)
-		if err != nil {
-			t.Logf("setenv error[%v]", err)
+func TestCompress(t *testing.T) {
+
+	src := t.TempDir()
+	files := map[string]string{
+		"VERSION":        "go1.21.0",
+		"bin/go":         "go command",
+		"src/fmt/doc.go": "package fmt",
+		"src/fmt/x.tmp":  "temporary",
+	}
+	for name, data := range files {
+		fn := filepath.Join(src, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(fn), 0755)
+		if err := os.WriteFile(fn, []byte(data), 0755); err != nil {
+			t.Fatal(err)
 		}
-	}(org)
+	}
+	symlink := runtime.GOOS != "windows"
+	if symlink {
+		if err := os.Symlink("go", filepath.Join(src, "bin", "golink")); err != nil {
+			t.Fatal(err)
+		}
+	}
 
-	err = os.Setenv("GOROOT", "")
+	m, err := golin.NewManager(golin.SetOutput(io.Discard, io.Discard), golin.SetProgress(false))
 	if err != nil {
-		t.Logf("GOROOT set error")
+		t.Fatal(err)
 	}
 
-	op := golin.DefaultOption()
-	op.StdIn = bytes.NewBufferString("Y\n")
-	err = golin.Create("1.12")
-	if err == nil {
-		t.Errorf("GOROOT setting not error")
+	tests := []struct {
+		format golin.CompressType
+		prefix string
+	}{
+		{golin.CompressZip, "go"},
+		{golin.CompressZip, ""},
+		{golin.CompressTarGz, "go"},
+		{golin.CompressTarGz, ""},
 	}
+	for _, test := range tests {
+
+		//BaseDirの外から実行しても相対的な名称になる
+		format := test.format
+		opts := golin.CompressOptions{
+			Format:  format,
+			BaseDir: src,
+			Prefix:  test.prefix,
+			Exclude: []string{"*.tmp"},
+		}
+		var buf bytes.Buffer
+		err := golin.Compress(&buf, &opts, ".")
+		if err != nil {
+			t.Fatalf("Compress(%d,%q) error[%v]", format, test.prefix, err)
+		}
+		data := buf.Bytes()
 
-	t.Logf("Work GOROOT[%s]", workROOT)
-	err = os.Setenv("GOROOT", workROOT)
-	if err != nil {
-		t.Logf("GOROOT set error")
+		//同じオプションで元のディレクトリに戻る
+		dst := filepath.Join(t.TempDir(), "sdk")
+		err = m.Decompress(context.Background(), bytes.NewReader(data), &opts, dst)
+		if err != nil {
+			t.Fatalf("Decompress(%d,%q) error[%v]", format, test.prefix, err)
+		}
+
+		//異なるプレフィックスのエントリはエラー
+		err = m.Decompress(context.Background(), bytes.NewReader(data),
+			&golin.CompressOptions{Format: format, Prefix: "other"}, filepath.Join(t.TempDir(), "other"))
+		if err == nil {
+			t.Errorf("Decompress(%d,%q) with other prefix is not error", format, test.prefix)
+		}
+
+		for name, data := range files {
+			b, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(name)))
+			if name == "src/fmt/x.tmp" {
+				if err == nil {
+					t.Errorf("Compress(%d) excluded file exists", format)
+				}
+				continue
+			}
+			if err != nil || string(b) != data {
+				t.Errorf("Compress(%d) %s [%s] error[%v]", format, name, b, err)
+			}
+		}
+
+		if info, err := os.Stat(filepath.Join(dst, "bin", "go")); err != nil || info.Mode().Perm()&0100 == 0 {
+			t.Errorf("Compress(%d) mode [%v] error[%v]", format, info, err)
+		}
+		if symlink {
+			link, err := os.Readlink(filepath.Join(dst, "bin", "golink"))
+			if err != nil || link != "go" {
+				t.Errorf("Compress(%d) symlink [%s] error[%v]", format, link, err)
+			}
+		}
 	}
+}
 
-	op.StdIn = bytes.NewBufferString("Y\n")
-	golin.SetOption(op)
-	err = golin.Create("1.12")
-	if err != nil {
-		t.Errorf("Create 1.12[%v]", err)
+func TestDecompressTarGzSymlink(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic link")
+	}
+
+	type entry struct {
+		name string
+		link string //空の場合はファイル
+	}
+	tests := []struct {
+		name    string
+		entries []entry
+	}{
+		{"absolute", []entry{{"go/evil", "/etc"}}},
+		{"parent", []entry{{"go/evil", "../../outside"}}},
+		{"through", []entry{{"go/b/y", ""}, {"go/a", "b"}, {"go/a/x", ""}}},
+		{"overwrite", []entry{{"go/b", "VERSION"}, {"go/b", ""}}},
 	}
 
-	//switch
-	op.StdIn = bytes.NewBufferString("Y\n")
-	golin.SetOption(op)
-	err = golin.Create("1.11")
+	m, err := golin.NewManager(golin.SetOutput(io.Discard, io.Discard), golin.SetProgress(false))
 	if err != nil {
-		t.Errorf("Create 1.11[%v]", err)
+		t.Fatal(err)
 	}
 
-	//reswitch
-	op.StdIn = bytes.NewBufferString("Y\n")
-	golin.SetOption(op)
-	err = golin.Create("1.12")
+	for _, test := range tests {
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gw)
+		for _, elm := range test.entries {
+			hdr := tar.Header{Name: elm.name, Mode: 0644, Typeflag: tar.TypeReg, Size: 4}
+			if elm.link != "" {
+				hdr.Typeflag = tar.TypeSymlink
+				hdr.Linkname = elm.link
+				hdr.Size = 0
+			}
+			if err := tw.WriteHeader(&hdr); err != nil {
+				t.Fatal(err)
+			}
+			if elm.link == "" {
+				tw.Write([]byte("evil"))
+			}
+		}
+		tw.Close()
+		gw.Close()
+
+		dst := filepath.Join(t.TempDir(), "sdk")
+		err = m.Decompress(context.Background(), &buf, &golin.CompressOptions{Format: golin.CompressTarGz, Prefix: "go"}, dst)
+		if err == nil {
+			t.Errorf("Decompress(%s) is not error", test.name)
+		}
+		if _, err := os.Stat(filepath.Join(dst, "b", "x")); err == nil {
+			t.Errorf("Decompress(%s) wrote through the symbolic link", test.name)
+		}
+	}
+}
+
+func TestDecompressZipSymlink(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic link")
+	}
+
+	type entry struct {
+		name string
+		link string //空の場合はファイル
+	}
+	tests := []struct {
+		name    string
+		entries []entry
+	}{
+		{"absolute", []entry{{"go/evil", "/etc"}}},
+		{"parent", []entry{{"go/evil", "../../outside"}}},
+		{"through", []entry{{"go/b/y", ""}, {"go/a", "b"}, {"go/a/x", ""}}},
+	}
+
+	m, err := golin.NewManager(golin.SetOutput(io.Discard, io.Discard), golin.SetProgress(false))
 	if err != nil {
-		t.Errorf("Create(exist) 1.12[%v]", err)
+		t.Fatal(err)
 	}
 
-	//Set Option Operation Y
+	for _, test := range tests {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for _, elm := range test.entries {
+			hdr := zip.FileHeader{Name: elm.name, Method: zip.Store}
+			body := "evil"
+			hdr.SetMode(0644)
+			if elm.link != "" {
+				hdr.SetMode(0777 | os.ModeSymlink)
+				body = elm.link
+			}
+			w, err := zw.CreateHeader(&hdr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.Write([]byte(body))
+		}
+		zw.Close()
+
+		dst := filepath.Join(t.TempDir(), "sdk")
+		err = m.Decompress(context.Background(), &buf, &golin.CompressOptions{Format: golin.CompressZip, Prefix: "go"}, dst)
+		if err == nil {
+			t.Errorf("Decompress(%s) is not error", test.name)
+		}
+		if _, err := os.Lstat(filepath.Join(dst, "evil")); err == nil {
+			t.Errorf("Decompress(%s) created the symbolic link", test.name)
+		}
+		if _, err := os.Stat(filepath.Join(dst, "b", "x")); err == nil {
+			t.Errorf("Decompress(%s) wrote through the symbolic link", test.name)
+		}
+	}
 }
 
 func BenchmarkParseVersion(b *testing.B) {
@@ -230,69 +444,11 @@ func BenchmarkSortVersion(b *testing.B) {
 	}
 }
 
-func ExamplePrint() {
+func ExamplePrintGoVersionList() {
 
-	err := golin.Print()
+	err := golin.PrintGoVersionList()
 	if err != nil {
 	}
-
-	// Output:
-	// 1.8beta1
-	// 1.8beta2
-	// 1.8rc1
-	// 1.8rc2
-	// 1.8rc3
-	// 1.8
-	// 1.8.1
-	// 1.8.2
-	// 1.8.3
-	// 1.8.4
-	// 1.8.5
-	// 1.8.6
-	// 1.8.7
-	// 1.9beta1
-	// 1.9beta2
-	// 1.9rc1
-	// 1.9rc2
-	// 1.9
-	// 1.9.1
-	// 1.9.2
-	// 1.9.3
-	// 1.9.4
-	// 1.9.5
-	// 1.9.6
-	// 1.9.7
-	// 1.10beta1
-	// 1.10beta2
-	// 1.10rc1
-	// 1.10rc2
-	// 1.10
-	// 1.10.1
-	// 1.10.2
-	// 1.10.3
-	// 1.10.4
-	// 1.10.5
-	// 1.10.6
-	// 1.10.7
-	// 1.10.8
-	// 1.11beta1
-	// 1.11beta2
-	// 1.11beta3
-	// 1.11rc1
-	// 1.11rc2
-	// 1.11
-	// 1.11.1
-	// 1.11.2
-	// 1.11.3
-	// 1.11.4
-	// 1.11.5
-	// 1.11.6
-	// 1.12beta1
-	// 1.12beta2
-	// 1.12rc1
-	// 1.12
-	// 1.12.1
-	//
 }
 
 // Test getHome()
@@ -1,12 +1,39 @@
 //go:build windows
-// +build windows
 
 package golin
 
 import (
 	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
 )
 
+// dataHome is user data directory (%LOCALAPPDATA%)
+func dataHome() string {
+	return os.Getenv("LOCALAPPDATA")
+}
+
+// lookupSudoUser is user who invoked sudo
+//
+// Windowsにはsudoがない為、常にnilを返します
+func lookupSudoUser() *sudoUser {
+	return nil
+}
+
+// fileOwner is uid of the file (not supported)
+func fileOwner(info os.FileInfo) (int, bool) {
+	return 0, false
+}
+
+// errorNotSameDevice is ERROR_NOT_SAME_DEVICE
+const errorNotSameDevice = syscall.Errno(17)
+
+// isCrossDeviceErrno is rename error between drives (ERROR_NOT_SAME_DEVICE)
+func isCrossDeviceErrno(err error) bool {
+	return err == errorNotSameDevice
+}
+
 //
 // getHome is USERPROFILE directory
 //
@@ -20,3 +47,35 @@ func getHome() string {
 func getDownloadExt() string {
 	return "zip"
 }
+
+//
+// setProcessGroup is kill the process tree on cancel
+//
+// キャンセル時にはtaskkillで子プロセスも含めて終了させます
+//
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+	}
+}
+
+//
+// replaceFile is replace dst with src
+//
+// 実行中のファイルは上書きできない為、退避してから置き換えます
+// 退避したファイルは次回の置き換え時に削除します
+//
+func replaceFile(src, dst string) error {
+	old := dst + ".old"
+	os.Remove(old)
+	err := os.Rename(dst, old)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	err = os.Rename(src, dst)
+	if err != nil {
+		os.Rename(old, dst)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,408 @@
+package i18n
+
+// en is English catalog
+var en = map[Key]string{
+
+	ConfirmRoot: `
+This command creates the Go SDK within the current GOROOT parent directory.
+It is recommended to specify a dedicated directory.
+
+%[1]s -
+   |- %[2]s
+   |- %[3]s [Download specified Go SDK]
+   |- %[4]s <- symbolic link that the creates.(Eval:%[3]s)
+
+By changing the environment variable GOROOT to [%[5]s], you can easily switch GOROOT.
+
+Is it OK?[Y/n]
+`,
+
+	Setting: `
+The latest Go (%[2]s) has been installed in %[1]s.
+Set the environment variable GOROOT to %[1]s and add GOROOT/bin to PATH.
+
+After that, you can switch the version with
+
+  $ golin 1.16
+`,
+
+	ConfirmSudo: `
+The symbolic link %[1]s cannot be created with your permissions.
+Only the link is created with administrator privileges by the following command.
+(The Go SDK is downloaded and extracted without privileges.)
+
+  $ %[2]s
+
+Is it OK?[Y/n]
+`,
+
+	Before: "Before:",
+	After:  "After :",
+
+	Usage: `Usage of golin:
+
+  golin [options] <command> [arguments]
+  golin [options] <version>
+
+  It is possible to switch by setting GOROOT to a symbolic link.
+  An argument that is not a command is used as a version only when it parses as one.
+    (e.g. go1.12.1 -> 1.12.1
+
+      golin 1.12.1
+
+`,
+
+	ExitStatus: `
+Exit status:
+
+  0    success
+  1    other error
+  2    invalid arguments
+  3    version does not exist
+  4    permission denied
+  5    checksum mismatch
+  6    network unavailable
+  7    already current
+  130  cancelled
+
+`,
+
+	Error:              "golin Error: %+v\n",
+	Success:            "Success.",
+	RequiredCommand:    "golin arguments required command(see golin help) or version(e.g. 1.15.6,1.16beta1).",
+	RequiredPath:       "golin install arguments required path",
+	RequiredVersion:    "golin arguments required version(e.g. 1.15.6, 1.16beta1).",
+	VerboseQuiet:       "-verbose and -quiet cannot be specified at the same time.",
+	UnknownLogFormat:   "unknown log format: %s (text or json)",
+	UnknownLang:        "unknown language: %s (en or ja)",
+	DevelopmentVersion: "golin development version",
+	EmptyVersion:       "version is empty.",
+	CommandVersion:     "golin version %s %s\nBuild Information:%s (%s)\n",
+
+	CommandsTitle:     "Commands:",
+	OptionsTitle:      "Options:",
+	HelpHint:          "Run \"golin help <command>\" for more information about a command.",
+	CommandUsage:      "Usage: golin %s",
+	UnknownCommand:    "unknown command: %q",
+	DidYouMean:        "\n\nDid you mean this?\n%s",
+	InvalidVersion:    "invalid version: %q (e.g. 1.15.6, 1.16beta1)",
+	RequiredShell:     "golin completion arguments required shell(bash,zsh,fish,powershell).",
+	UnknownShell:      "unknown shell: %s (bash, zsh, fish or powershell)",
+	ConflictFlags:     "%s and %s cannot be specified at the same time.",
+	InventoryProblems: "%d problems found.",
+	InventoryHeader:   "DIR\tVERSION\tMETHOD\tINSTALLED\tLAST USED\tSTATUS",
+	ListHeader:        "  VERSION\tINSTALLED\tSIZE\tRELEASED",
+	VerifyResult:      "%s: %d files, %d missing, %d modified, %d extra (compared with %s)",
+	VerifyRepaired:    "%d files repaired.",
+	VerifyDamaged:     "%s has %d damaged files. Run with -repair to re-extract them.",
+	VerifyMissing:     "missing   %s",
+	VerifyModified:    "modified  %s",
+	VerifyExtra:       "extra     %s",
+	RepairedFile:      "repaired  %s",
+	NoUpdates:         "All installed minor versions are up to date.",
+	OutdatedHeader:    "  INSTALLED\tLATEST\t",
+	OutdatedSecurity:  "security",
+	UnknownChannel:    "Unknown channel: %s (patch, stable or pre)",
+	Upgraded:          "Upgraded %s -> %s",
+	Pruned:            "Removed %s",
+	SelfUpToDate:      "golin %s is up to date.",
+	SelfUpdated:       "Updated golin %s -> %s (%s)",
+	WarnUnsupported:   "warning: go%s is no longer supported (supported: %s). Please upgrade.",
+	WarnVulns:         "warning: go%s has %d known vulnerabilities. Run golin doctor for details.",
+	DoctorVersion:     "version        : go%s (released %s)\n",
+	DoctorSupport:     "support        : %s (supported: %s)\n",
+	DoctorUpdate:      "update         : go%s is available\n",
+	DoctorUpToDate:    "update         : up to date\n",
+	DoctorVulns:       "vulnerabilities: %d\n",
+	DoctorNoVulnDB:    "vulnerabilities: not checked (no vulndb, see -vulndb or GOVULNDB)\n",
+	DoctorNotes:       "release notes  : %s\n",
+	DoctorProblems:    "%d problems found.",
+	Cleaned:           "%d leftovers removed.",
+	CleanWrapper:      "wrapper  %s",
+	CleanSDK:          "sdk      %s",
+	CleanModule:       "module   %s",
+	CleanUntracked:    "kept     %s (not created by golin)",
+	CleanDryRun:       "%d leftovers would be removed.",
+	NothingToClean:    "Nothing to clean.",
+	ShimNotInstalled:  "golin shim: go%[1]s (%[2]s) is not installed. Run \"golin %[1]s\" or \"golin upgrade\" to install it.",
+	ShimInstalled: `
+The shims have been installed in %[1]s.
+Add it to the beginning of PATH (GOROOT is set by the shims for each command).
+
+  export PATH=%[1]s:$PATH
+
+The version is chosen per invocation from $GOLIN_VERSION, .go-version
+in the current or a parent directory, "golin shim default" and the symbolic link.
+`,
+	RequiredShimCommand:      "golin shim arguments required sub command (%s).",
+	WarnToolchain:            "warning: the go command of go%s may switch to another toolchain (GOTOOLCHAIN=%s). Use -toolchain-local to keep the linked SDK.",
+	ToolchainImported:        "Imported go%s from %s",
+	NoToolchains:             "No toolchains of this platform in the module cache.",
+	RequiredToolchainCommand: "golin toolchain arguments required sub command (%s).",
+	Imported:                 "Imported go%s from %s (%s)",
+	ImportCandidates:         "%d SDKs would be imported.",
+	ImportSkipped:            "Skipped %s: %v",
+	NoSDKsFound:              "No SDKs to import.",
+	RequiredImportPath:       "golin import arguments required path of the SDK or -scan.",
+	UnknownImportMode:        "Unknown import mode: %s (copy, move or link)",
+	RequiredTool:             "golin which arguments required tool(e.g. go, gofmt, vet).",
+	CurrentInfo:              "Version : %s\nPath    : %s\nLink    : %s\n",
+	GoCommand:                "go      : %s\n",
+	GoNotFound:               "go command is not found in PATH. Add %s to PATH.",
+	GoNotInSDK:               "go command in PATH does not belong to this SDK. Add %s to the beginning of PATH.",
+
+	CmdInstall:    "install Go and create the symbolic link",
+	CmdSwitch:     "switch the symbolic link to the version",
+	CmdList:       "list installable and installed versions",
+	CmdRemove:     "remove an installed version",
+	CmdCurrent:    "print the version of the symbolic link",
+	CmdWhich:      "print the path of the tool in the version",
+	CmdInventory:  "validate the installed versions with the manifest",
+	CmdVerify:     "check the installed files with the recorded hashes",
+	CmdOutdated:   "list patch updates of the installed minor versions",
+	CmdUpgrade:    "install and switch to the newest patch release",
+	CmdClean:      "remove leftovers of golang.org/dl",
+	CmdDoctor:     "check the support status and vulnerabilities of the linked version",
+	CmdShim:       "switch the version per directory with go and gofmt shims",
+	CmdToolchain:  "list and import toolchains downloaded by the go command",
+	CmdImport:     "import existing SDKs (/usr/local/go, ~/sdk, Homebrew etc.) into the root",
+	CmdDev:        "build the latest development version",
+	CmdSelfUpdate: "update golin to the latest release",
+	CmdVersion:    "print golin version",
+	CmdCompletion: "print the shell completion script",
+	CmdHelp:       "print help of the command",
+
+	HelpInstall: `  Installs the latest Go (or the specified version).
+  {path} is the directory that becomes the parent of GOROOT.
+
+     e.g) golin install /usr/local/go
+
+  Go is placed in {path}/{version} and
+  the symbolic link {path}/current is created for it.
+  After that, set the environment variable GOROOT to {path}/current.
+`,
+	HelpSwitch: `  Switches the symbolic link to the version.
+  Please remove the "go" for the specification of the version.
+    (e.g. go1.12.1 -> 1.12.1
+
+      golin switch 1.12.1
+      golin 1.12.1
+
+  If the version does not exist, it is downloaded.
+  "tip" switches to the version built by dev if it exists.
+
+  The link name "current" can be changed with -d
+
+     e.g.) golin -d root 1.16
+
+  The symbolic link becomes {path}/root, so set it to GOROOT.
+`,
+	HelpList: `  Lists the versions that can be installed and the installed versions.
+  The columns are the version, the install date, the disk size and the release date.
+  The version of the symbolic link is marked with "*".
+  A list of downloads is available at the link below.
+
+      https://github.com/golang/dl
+
+      golin list -installed
+      golin list -stable -minor 1.21
+      golin list -latest-per-minor
+
+  A warning is printed when the version of the symbolic link is no longer
+  supported or has known vulnerabilities (-vulndb).
+`,
+	HelpRemove: `  Removes the installed version.
+  The version of the symbolic link cannot be removed.
+`,
+	HelpCurrent: `  Prints the version, the directory and the symbolic link.
+  It also checks whether the go command in PATH belongs to the SDK.
+`,
+	HelpWhich: `  Prints the path of go, gofmt or a pkg/tool binary (vet, cover, ...)
+  in the installed version. If the version is omitted, the version of the symbolic link is used.
+
+      golin which gofmt
+      golin which vet 1.21.0
+`,
+	HelpInventory: `  Validates the directories in the root with the manifest (.golin.json).
+  The manifest records the version, the source URL, the SHA256, the install time,
+  the last used time, the install method (archive, golang.org/dl, source) and the platform.
+
+      ok         recorded and valid
+      untracked  the directory is not recorded in the manifest
+      missing    recorded but the directory does not exist
+      corrupted  the go command does not exist or VERSION does not match
+
+  Exits with status 1 if a problem is found.
+`,
+	HelpVerify: `  Checks the files of the installed version (the version of the symbolic link if omitted)
+  with the file list and the SHA256 recorded at install time.
+  If the list is not recorded, it is derived from the release archive.
+  Missing, modified and extra files are reported.
+
+  -repair re-extracts only the missing and modified files from the release archive.
+  Extra files are not removed.
+
+      golin verify
+      golin verify -repair 1.21.0
+`,
+	HelpOutdated: `  Lists the installed minor versions that have a newer patch release.
+  The version of the symbolic link is marked with "*".
+  "security" is displayed when the release metadata marks a security release.
+`,
+	HelpUpgrade: `  Installs the newest release of the channel and switches to it.
+
+      patch   newest patch release of the current minor version (default)
+      stable  newest stable release
+      pre     newest release including beta and rc
+
+  -prune removes the previous version after switching.
+  Exits with status 7 if the current version is already the newest.
+
+      golin upgrade
+      golin upgrade -channel stable -prune
+`,
+	HelpClean: `  Removes the files that golang.org/dl left outside the root:
+  the $GOPATH/bin/go{version} commands, the ~/sdk/go{version} directories
+  and golang.org/dl in the module cache.
+  Only the files created by golin (recorded in .golin.json) are removed.
+  The go{version} commands and ~/sdk/go{version} of the versions installed
+  with golang.org/dl that are not recorded are reported and kept.
+  -n prints the files without removing them.
+
+      golin clean -n
+      golin clean
+`,
+	HelpDoctor: `  Checks the version of the symbolic link.
+  It prints the release date, the support status, the newer patch release,
+  the known vulnerabilities of the standard library and the release notes.
+  The two latest minor versions are supported.
+  It exits with an error when a problem is found.
+
+  -vulndb is the Go vulnerability database (OSV JSON) used offline.
+  It is a mirror URL (index/modules.json), a copy of the mirror, a directory
+  of JSON files or a JSON file. The default is the GOVULNDB environment variable.
+
+      golin doctor
+      golin doctor -vulndb ~/vulndb
+`,
+	HelpShim: `  Shim mode is an alternative to the symbolic link of GOROOT.
+  The go and gofmt shims run the real command of the version with GOROOT set,
+  so no link permission is needed and tools never cache a stale GOROOT.
+  The version is chosen per invocation in this order:
+
+      $GOLIN_VERSION      environment variable
+      .go-version         in the current or a parent directory
+      golin shim default  global default (.golin-version in the root)
+      symbolic link       the version of golin switch
+
+  A minor version (e.g. 1.21) uses the newest installed patch release.
+
+      install  copies golin as go and gofmt into -dir (default: {root}/.shims)
+      default  sets the global default (no version removes it)
+      pin      writes .go-version in the current directory
+      which    prints the resolved SDK and where the version came from
+
+      golin shim install
+      golin shim pin 1.21
+      GOLIN_VERSION=1.20.8 go version
+`,
+	HelpToolchain: `  Since Go 1.21 the go command downloads other toolchains by itself
+  (GOTOOLCHAIN, the toolchain line of go.mod) into the module cache
+  as golang.org/toolchain@v0.0.1-go{version}.{GOOS}-{GOARCH}.
+
+      list    prints the toolchains of this platform in the module cache
+      import  copies them (all or the version) into the root
+              without downloading them again
+
+  golin switch and upgrade also import the version from the module cache
+  when it is there. -toolchain-local writes GOTOOLCHAIN=local to go.env
+  of the SDK on switch, so the go command keeps the linked SDK. A switch
+  without it restores the original line.
+
+      golin toolchain list
+      golin toolchain import 1.22.0
+      golin -toolchain-local 1.22.0
+`,
+	HelpImport: `  Takes existing Go trees into the root under the version of their VERSION file
+  and records them in the manifest, so they are not downloaded again.
+
+  -scan finds the SDKs in the known locations: /usr/local/go, ~/sdk/go1.x
+  (golang.org/dl), Homebrew, apt (/usr/lib/go-1.x), GOROOT of the go command
+  in PATH and golang.org/toolchain in the module cache.
+  Versions already in the root are skipped. -n prints them without importing.
+
+  -mode is how the SDK is taken in:
+
+      copy  copies the SDK (default, the original is kept)
+      move  moves the SDK into the root
+      link  creates a symbolic link to the SDK in the root
+            (golin remove deletes the link only)
+
+  Toolchains in the module cache are always copied.
+
+      golin import /usr/local/go
+      golin import -mode move ~/sdk/go1.21.0
+      golin import -scan -n
+`,
+	HelpDev: `  Builds and updates the latest development version (gotip) (it takes a while).
+  dev keeps the previous source and builds only when there are new commits.
+  The build runs in a separate directory and is swapped in when it completes.
+`,
+	HelpSelfUpdate: `  Downloads the latest golin release for the running OS and architecture,
+  verifies it with the SHA256SUMS of the release and replaces the executable.
+  The executable is written next to the current one and renamed over it,
+  so it is never left half-written.
+
+  -release-url is a URL returning JSON in the GitHub Releases API format
+  (tag_name and assets), e.g. a local file server.
+
+      golin self-update
+      sudo golin self-update
+`,
+	HelpVersion: `  Prints the version of golin.
+`,
+	HelpCompletion: `  Prints the completion script of the shell.
+  Commands, installed versions and installable versions are completed.
+
+      bash       source <(golin completion bash)
+      zsh        golin completion zsh > "${fpath[1]}/_golin"
+      fish       golin completion fish > ~/.config/fish/completions/golin.fish
+      powershell golin completion powershell | Out-String | Invoke-Expression
+`,
+	HelpHelp: `  Prints the usage of golin or the command.
+`,
+
+	FlagLink:                 "symbolic link name",
+	FlagLinkDir:              "directory of the symbolic link (default: the root)",
+	FlagGOROOT:               "GOROOT used instead of the environment variable (e.g. under sudo)",
+	FlagToolchainLocal:       "write GOTOOLCHAIN=local to go.env of the SDK on switch (Go 1.21 or later)",
+	FlagAllowMissingChecksum: "install archives without a published checksum (not verified)",
+	FlagVerbose:              "print debug logs",
+	FlagQuiet:                "print warnings and errors only",
+	FlagLogFormat:            "log format (text or json)",
+	FlagLang:                 "message language (en or ja)",
+
+	FlagInstalled:      "installed versions only",
+	FlagAvailable:      "versions that are not installed only",
+	FlagStable:         "stable releases only",
+	FlagPre:            "beta and rc only",
+	FlagMinor:          "versions of the minor version (e.g. 1.21)",
+	FlagLatestPerMinor: "latest version of each minor version only",
+
+	FlagRepair: "re-extract the missing and modified files",
+
+	FlagChannel: "upgrade channel (patch, stable or pre)",
+	FlagPrune:   "remove the previous version after switching",
+
+	FlagReleaseURL: "latest release location (GitHub Releases API format)",
+
+	FlagVulnDB: "Go vulnerability database (path or mirror URL, default $GOVULNDB)",
+
+	FlagDryRun: "print the files without removing them",
+
+	FlagScan:         "find the SDKs in the known locations",
+	FlagImportMode:   "how the SDK is taken in (copy, move or link)",
+	FlagImportDryRun: "print the SDKs without importing them",
+
+	FlagShimDir: "directory of the shims (default: {root}/.shims)",
+}
@@ -0,0 +1,108 @@
+/*
+   Package i18n is message catalogs of golin
+
+   golinの表示するメッセージを言語ごとのカタログで管理します
+   言語は--langの指定、環境変数LC_ALL,LC_MESSAGES,LANGの順に決定し、
+   カタログに存在しない言語は英語になります
+*/
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Lang is message language
+type Lang string
+
+const (
+	English  Lang = "en"
+	Japanese Lang = "ja"
+
+	DefaultLang = English
+)
+
+// Key is message key
+type Key string
+
+var catalogs = map[Lang]map[Key]string{
+	English:  en,
+	Japanese: ja,
+}
+
+// Langs is supported languages
+func Langs() []Lang {
+	return []Lang{English, Japanese}
+}
+
+// Catalog is messages of the language
+//
+// 存在しない言語の場合はnilを返します
+func Catalog(lang Lang) map[Key]string {
+	return catalogs[lang]
+}
+
+// Parse is language of the locale
+//
+// "ja_JP.UTF-8"や"ja"などのロケールから言語を決定します
+// カタログに存在しない場合はfalseを返します
+func Parse(locale string) (Lang, bool) {
+
+	l := strings.ToLower(locale)
+	if idx := strings.IndexAny(l, "_.@-"); idx != -1 {
+		l = l[:idx]
+	}
+
+	lang := Lang(l)
+	if _, ok := catalogs[lang]; !ok {
+		return DefaultLang, false
+	}
+	return lang, true
+}
+
+// Detect is language of the command
+//
+// 引数(--lang)、LC_ALL、LC_MESSAGES、LANGの順に最初に指定されている値を利用します
+func Detect(flag string) Lang {
+	for _, locale := range []string{flag, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")} {
+		if locale == "" {
+			continue
+		}
+		lang, _ := Parse(locale)
+		return lang
+	}
+	return DefaultLang
+}
+
+// Printer is message formatter of the language
+type Printer struct {
+	lang Lang
+}
+
+// NewPrinter is create Printer
+func NewPrinter(lang Lang) *Printer {
+	if _, ok := catalogs[lang]; !ok {
+		lang = DefaultLang
+	}
+	return &Printer{lang: lang}
+}
+
+// Lang is language of the printer
+func (p *Printer) Lang() Lang {
+	return p.lang
+}
+
+// Sprintf is format the message
+//
+// 言語のカタログに存在しない場合は英語、英語にも存在しない場合はキーを利用します
+func (p *Printer) Sprintf(key Key, args ...interface{}) string {
+	format, ok := catalogs[p.lang][key]
+	if !ok {
+		format, ok = catalogs[DefaultLang][key]
+		if !ok {
+			format = string(key)
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
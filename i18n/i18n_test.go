@@ -0,0 +1,202 @@
+package i18n_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/shizuokago/golin/v2/i18n"
+)
+
+// verbs is format verbs of the message
+var verbs = regexp.MustCompile(`%(\[\d+\])?[+#]?[a-z]`)
+
+func formatVerbs(msg string) []string {
+	list := verbs.FindAllString(msg, -1)
+	sort.Strings(list)
+	return list
+}
+
+func TestCatalog(t *testing.T) {
+
+	base := i18n.Catalog(i18n.DefaultLang)
+	if len(base) == 0 {
+		t.Fatalf("default catalog is empty")
+	}
+
+	for _, lang := range i18n.Langs() {
+
+		catalog := i18n.Catalog(lang)
+		if catalog == nil {
+			t.Errorf("catalog[%s] not found", lang)
+			continue
+		}
+
+		for key, msg := range base {
+			target, ok := catalog[key]
+			if !ok {
+				t.Errorf("catalog[%s] missing key[%s]", lang, key)
+				continue
+			}
+
+			bv := formatVerbs(msg)
+			tv := formatVerbs(target)
+			if len(bv) != len(tv) {
+				t.Errorf("catalog[%s] key[%s] verbs %v != %v", lang, key, tv, bv)
+				continue
+			}
+			for idx := range bv {
+				if bv[idx] != tv[idx] {
+					t.Errorf("catalog[%s] key[%s] verbs %v != %v", lang, key, tv, bv)
+					break
+				}
+			}
+		}
+
+		for key := range catalog {
+			if _, ok := base[key]; !ok {
+				t.Errorf("catalog[%s] key[%s] not in default catalog", lang, key)
+			}
+		}
+	}
+}
+
+// declaredKeys is Key constants of the package
+//
+// パッケージのソースを解析し、Key型の定数の名前と値を返します
+func declaredKeys(t *testing.T) map[string]i18n.Key {
+
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatalf("ReadDir error[%v]", err)
+	}
+
+	keys := make(map[string]i18n.Key)
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatalf("ParseFile(%s) error[%v]", name, err)
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if typ, ok := vs.Type.(*ast.Ident); !ok || typ.Name != "Key" {
+					continue
+				}
+				for idx, ident := range vs.Names {
+					lit, ok := vs.Values[idx].(*ast.BasicLit)
+					if !ok || lit.Kind != token.STRING {
+						t.Errorf("key %s is not a string literal", ident.Name)
+						continue
+					}
+					v, err := strconv.Unquote(lit.Value)
+					if err != nil {
+						t.Fatalf("Unquote(%s) error[%v]", lit.Value, err)
+					}
+					keys[ident.Name] = i18n.Key(v)
+				}
+			}
+		}
+	}
+	return keys
+}
+
+func TestCatalogKeys(t *testing.T) {
+
+	keys := declaredKeys(t)
+	if len(keys) == 0 {
+		t.Fatalf("Key constants not found")
+	}
+
+	base := i18n.Catalog(i18n.DefaultLang)
+	names := make(map[i18n.Key]string)
+	for name, key := range keys {
+		if other, ok := names[key]; ok {
+			t.Errorf("key[%s] is declared as %s and %s", key, other, name)
+		}
+		names[key] = name
+
+		if _, ok := base[key]; !ok {
+			t.Errorf("catalog[%s] missing key %s[%s]", i18n.DefaultLang, name, key)
+		}
+	}
+
+	for key := range base {
+		if _, ok := names[key]; !ok {
+			t.Errorf("catalog[%s] key[%s] is not declared", i18n.DefaultLang, key)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+
+	tests := []struct {
+		locale string
+		lang   i18n.Lang
+		ok     bool
+	}{
+		{"ja_JP.UTF-8", i18n.Japanese, true},
+		{"ja", i18n.Japanese, true},
+		{"JA-jp", i18n.Japanese, true},
+		{"en_US.UTF-8", i18n.English, true},
+		{"C", i18n.English, false},
+		{"fr_FR", i18n.English, false},
+	}
+
+	for _, test := range tests {
+		lang, ok := i18n.Parse(test.locale)
+		if lang != test.lang || ok != test.ok {
+			t.Errorf("Parse(%s) [%s,%v] != [%s,%v]", test.locale, lang, ok, test.lang, test.ok)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "ja_JP.UTF-8")
+
+	if lang := i18n.Detect(""); lang != i18n.Japanese {
+		t.Errorf("Detect LANG [%s]", lang)
+	}
+	if lang := i18n.Detect("en"); lang != i18n.English {
+		t.Errorf("Detect flag [%s]", lang)
+	}
+
+	t.Setenv("LC_ALL", "C")
+	if lang := i18n.Detect(""); lang != i18n.English {
+		t.Errorf("Detect LC_ALL [%s]", lang)
+	}
+}
+
+func TestPrinter(t *testing.T) {
+
+	p := i18n.NewPrinter(i18n.Japanese)
+	if msg := p.Sprintf(i18n.UnknownLang, "fr"); msg != "言語が不明です: fr (enかja)" {
+		t.Errorf("Sprintf [%s]", msg)
+	}
+
+	p = i18n.NewPrinter("fr")
+	if p.Lang() != i18n.English {
+		t.Errorf("NewPrinter unknown language [%s]", p.Lang())
+	}
+	if msg := p.Sprintf("no_such_key"); msg != "no_such_key" {
+		t.Errorf("Sprintf missing key [%s]", msg)
+	}
+}
@@ -0,0 +1,411 @@
+package i18n
+
+// ja is Japanese catalog
+var ja = map[Key]string{
+
+	ConfirmRoot: `
+このコマンドは現在のGOROOTの上の階層にGo SDKを作成します。
+専用のディレクトリを指定することをおすすめします。
+
+%[1]s -
+   |- %[2]s
+   |- %[3]s [指定したGo SDKをダウンロード]
+   |- %[4]s <- 作成するシンボリックリンク(リンク先:%[3]s)
+
+環境変数GOROOTを[%[5]s]に変更することで、GOROOTを簡単に切り替えられます。
+
+よろしいですか？[Y/n]
+`,
+
+	Setting: `
+%[1]s にGoの最新バージョン(%[2]s)をインストールしました。
+環境変数GOROOTに%[1]sを設定し、PATHをGOROOT/binに設定してください。
+
+今後は
+
+  $ golin 1.16
+
+などでバージョンの切り替えが可能になります。
+`,
+
+	ConfirmSudo: `
+現在の権限ではシンボリックリンク %[1]s を作成できません。
+以下のコマンドでリンクの作成のみを管理者の権限で実行します。
+(Go SDKのダウンロード、展開は権限を昇格せずに行います)
+
+  $ %[2]s
+
+よろしいですか？[Y/n]
+`,
+
+	Before: "切り替え前:",
+	After:  "切り替え後:",
+
+	Usage: `golinの使い方:
+
+  golin [options] <command> [arguments]
+  golin [options] <version>
+
+  GOROOTにシンボリックリンクを設定することで、バージョンを切り替えます。
+  コマンドでない引数はバージョンとして解析できる場合のみ切り替えを行います。
+    (例: go1.12.1 -> 1.12.1
+
+      golin 1.12.1
+
+`,
+
+	ExitStatus: `
+終了コード:
+
+  0    正常終了
+  1    その他のエラー
+  2    引数の誤り
+  3    バージョンが存在しない
+  4    権限がない
+  5    チェックサムの不一致
+  6    ネットワークに接続できない
+  7    既に切り替え済み
+  130  キャンセル
+
+`,
+
+	Error:              "golin エラー: %+v\n",
+	Success:            "成功しました。",
+	RequiredCommand:    "golinの引数にはコマンド(「golin help」を参照)かバージョン(例: 1.15.6,1.16beta1)が必要です。",
+	RequiredPath:       "golin installの引数にはパスが必要です。",
+	RequiredVersion:    "golinの引数にはバージョン(例: 1.15.6, 1.16beta1)が必要です。",
+	VerboseQuiet:       "-verboseと-quietは同時に指定できません。",
+	UnknownLogFormat:   "ログの出力形式が不明です: %s (textかjson)",
+	UnknownLang:        "言語が不明です: %s (enかja)",
+	DevelopmentVersion: "golin 開発バージョン",
+	EmptyVersion:       "バージョンが設定されていません。",
+	CommandVersion:     "golin バージョン %s %s\nビルド情報:%s (%s)\n",
+
+	CommandsTitle:     "コマンド:",
+	OptionsTitle:      "オプション:",
+	HelpHint:          "コマンドの詳細は「golin help <command>」で表示されます。",
+	CommandUsage:      "使い方: golin %s",
+	UnknownCommand:    "コマンドが不明です: %q",
+	DidYouMean:        "\n\n以下のコマンドではありませんか？\n%s",
+	InvalidVersion:    "バージョンが不正です: %q (例: 1.15.6, 1.16beta1)",
+	RequiredShell:     "golin completionの引数にはシェル(bash,zsh,fish,powershell)が必要です。",
+	UnknownShell:      "シェルが不明です: %s (bash,zsh,fish,powershellのいずれか)",
+	ConflictFlags:     "%sと%sは同時に指定できません。",
+	InventoryProblems: "%d件の問題が見つかりました。",
+	InventoryHeader:   "ディレクトリ\tバージョン\t方法\tインストール日時\t最終利用日時\t状態",
+	ListHeader:        "  バージョン\tインストール日時\tサイズ\tリリース日",
+	VerifyResult:      "%s: %dファイル、不足%d、変更%d、追加%d (比較元 %s)",
+	VerifyRepaired:    "%dファイルを修復しました。",
+	VerifyDamaged:     "%sに%d件の破損したファイルがあります。-repairで展開し直してください。",
+	VerifyMissing:     "不足  %s",
+	VerifyModified:    "変更  %s",
+	VerifyExtra:       "追加  %s",
+	RepairedFile:      "修復  %s",
+	NoUpdates:         "インストール済みのマイナーバージョンはすべて最新です。",
+	OutdatedHeader:    "  インストール済み\t最新\t",
+	OutdatedSecurity:  "セキュリティ修正",
+	UnknownChannel:    "不明なチャンネルです: %s (patch, stable, pre)",
+	Upgraded:          "%s -> %s に更新しました",
+	Pruned:            "%sを削除しました",
+	SelfUpToDate:      "golin %sは最新です。",
+	SelfUpdated:       "golinを%s -> %sに更新しました (%s)",
+	WarnUnsupported:   "警告: go%sはサポートが終了しています(サポート中: %s)。アップグレードしてください。",
+	WarnVulns:         "警告: go%sには%d件の既知の脆弱性があります。詳細はgolin doctorで確認してください。",
+	DoctorVersion:     "バージョン    : go%s (リリース日 %s)\n",
+	DoctorSupport:     "サポート      : %s (サポート中: %s)\n",
+	DoctorUpdate:      "更新          : go%sがあります\n",
+	DoctorUpToDate:    "更新          : 最新です\n",
+	DoctorVulns:       "脆弱性        : %d件\n",
+	DoctorNoVulnDB:    "脆弱性        : 未確認(-vulndbまたはGOVULNDBで指定してください)\n",
+	DoctorNotes:       "リリースノート: %s\n",
+	DoctorProblems:    "%d件の問題が見つかりました。",
+	Cleaned:           "%d件の不要なファイルを削除しました。",
+	CleanWrapper:      "ラッパー  %s",
+	CleanSDK:          "SDK  %s",
+	CleanModule:       "モジュール  %s",
+	CleanUntracked:    "対象外  %s (golinが作成していない為削除しません)",
+	CleanDryRun:       "%d件の不要なファイルを削除します(-n)。",
+	NothingToClean:    "削除するファイルはありません。",
+	ShimNotInstalled:  "golin shim: go%[1]s (%[2]s) はインストールされていません。\"golin %[1]s\" または \"golin upgrade\" でインストールしてください。",
+	ShimInstalled: `
+%[1]s にシムをインストールしました。
+PATHの先頭に追加してください(GOROOTはシムがコマンドごとに設定します)。
+
+  export PATH=%[1]s:$PATH
+
+バージョンは実行ごとに $GOLIN_VERSION、カレントまたは上の階層の.go-version、
+"golin shim default"、シンボリックリンクの順に決定します。
+`,
+	RequiredShimCommand:      "golin shimの引数にはサブコマンド(%s)が必要です。",
+	WarnToolchain:            "警告: go%sのgoコマンドは別のツールチェインに切り替わる可能性があります(GOTOOLCHAIN=%s)。リンク先のSDKを維持する場合は-toolchain-localを指定してください。",
+	ToolchainImported:        "go%sを取り込みました(%s)",
+	NoToolchains:             "モジュールキャッシュにこのプラットフォームのツールチェインはありません。",
+	RequiredToolchainCommand: "golin toolchainの引数にはサブコマンド(%s)が必要です。",
+	Imported:                 "go%sを取り込みました(%s、%s)",
+	ImportCandidates:         "%d件のSDKを取り込みます(-n)。",
+	ImportSkipped:            "%sは取り込みませんでした: %v",
+	NoSDKsFound:              "取り込むSDKはありません。",
+	RequiredImportPath:       "golin importの引数にはSDKのパスか-scanが必要です。",
+	UnknownImportMode:        "取り込みの方法が不明です: %s (copy、moveまたはlink)",
+	RequiredTool:             "golin whichの引数にはツール(例: go, gofmt, vet)が必要です。",
+	CurrentInfo:              "バージョン : %s\nパス       : %s\nリンク     : %s\n",
+	GoCommand:                "go         : %s\n",
+	GoNotFound:               "PATHにgoコマンドが存在しません。PATHに%sを追加してください。",
+	GoNotInSDK:               "PATHのgoコマンドはこのSDKのものではありません。PATHの先頭に%sを追加してください。",
+
+	CmdInstall:    "Goをインストールしてシンボリックリンクを作成",
+	CmdSwitch:     "シンボリックリンクをバージョンに切り替え",
+	CmdList:       "インストール可能、インストール済みのバージョンを表示",
+	CmdRemove:     "インストール済みのバージョンを削除",
+	CmdCurrent:    "シンボリックリンクのバージョンを表示",
+	CmdWhich:      "バージョンのツールのパスを表示",
+	CmdInventory:  "インストール済みのバージョンをマニフェストで検証",
+	CmdVerify:     "インストールしたファイルを記録したハッシュで検証",
+	CmdOutdated:   "インストール済みのマイナーバージョンの更新を表示",
+	CmdUpgrade:    "最新のパッチリリースをインストールして切り替え",
+	CmdClean:      "golang.org/dlの不要なファイルを削除",
+	CmdDoctor:     "リンク先のバージョンのサポート状況と脆弱性を確認",
+	CmdShim:       "goとgofmtのシムでディレクトリごとにバージョンを切り替え",
+	CmdToolchain:  "goコマンドがダウンロードしたツールチェインを表示、取り込み",
+	CmdImport:     "既存のSDK(/usr/local/go、~/sdk、Homebrew等)をルートに取り込み",
+	CmdDev:        "最新の開発バージョンをビルド",
+	CmdSelfUpdate: "golinを最新のリリースに更新",
+	CmdVersion:    "golinのバージョンを表示",
+	CmdCompletion: "シェルの補完スクリプトを表示",
+	CmdHelp:       "コマンドの説明を表示",
+
+	HelpInstall: `  最新(または指定した)バージョンのGoをインストールします。
+  {path}はGOROOTの元になる位置を指定します。
+
+     e.g) golin install /usr/local/go
+
+  これを行うことで{path}/{version}にGoが設定され、
+  そのGoに対して{path}/current にシンボリックリンクを作成します。
+  その後、{path}/currentに対して、GOROOTの環境変数を設定してください。
+`,
+	HelpSwitch: `  シンボリックリンクをバージョンに切り替えます。
+  バージョンの指定から「go」を除いてください。
+    (例: go1.12.1 -> 1.12.1
+
+      golin switch 1.12.1
+      golin 1.12.1
+
+  バージョンが存在しない場合はダウンロードを行います。
+  「tip」はdevでビルドしたバージョンが存在する場合、切り替えるのみで終了します。
+
+  また-d を指定することでcurrentを変更することができます
+
+     e.g.) golin -d root 1.16
+
+  これにより切り替え先のシンボリックリンクが{path}/rootになりますので、
+  そこをGOROOTに指定してください。
+`,
+	HelpList: `  インストール可能なバージョンとインストール済みのバージョンを一覧で表示します。
+  バージョン、インストール日時、ディスク上のサイズ、リリース日を表示します。
+  シンボリックリンクのバージョンには「*」を表示します。
+  ダウンロード可能なバージョンは以下で確認できます。
+
+      https://github.com/golang/dl
+
+      golin list -installed
+      golin list -stable -minor 1.21
+      golin list -latest-per-minor
+
+  シンボリックリンクのバージョンのサポートが終了している場合、
+  既知の脆弱性がある場合(-vulndb)は警告を表示します。
+`,
+	HelpRemove: `  インストール済みのバージョンを削除します。
+  シンボリックリンクのバージョンは削除できません。
+`,
+	HelpCurrent: `  シンボリックリンクのバージョン、ディレクトリ、リンクを表示します。
+  PATHのgoコマンドがそのSDKのものかも確認します。
+`,
+	HelpWhich: `  インストール済みのバージョンのgo、gofmt、pkg/toolのコマンド(vet、cover等)のパスを表示します。
+  バージョンを省略した場合はシンボリックリンクのバージョンを対象にします。
+
+      golin which gofmt
+      golin which vet 1.21.0
+`,
+	HelpInventory: `  ルートのディレクトリをマニフェスト(.golin.json)で検証します。
+  マニフェストにはバージョン、ダウンロード元、SHA256、インストール日時、
+  最終利用日時、インストール方法(archive、golang.org/dl、source)、プラットフォームを記録します。
+
+      ok         記録があり問題なし
+      untracked  マニフェストに記録がない
+      missing    記録があるがディレクトリが存在しない
+      corrupted  goコマンドが存在しない、またはVERSIONが一致しない
+
+  問題が見つかった場合は終了コード1で終了します。
+`,
+	HelpVerify: `  インストール済みのバージョン(省略時はシンボリックリンクのバージョン)のファイルを
+  インストール時に記録したファイルの一覧とSHA256で検証します。
+  記録がない場合はリリースのアーカイブから一覧を作成します。
+  存在しない、内容が異なる、追加されたファイルを表示します。
+
+  -repairを指定すると存在しない、内容が異なるファイルのみアーカイブから展開し直します。
+  追加されたファイルは削除しません。
+
+      golin verify
+      golin verify -repair 1.21.0
+`,
+	HelpOutdated: `  新しいパッチリリースがあるインストール済みのマイナーバージョンを表示します。
+  シンボリックリンクのバージョンには「*」を表示します。
+  リリースのメタデータにセキュリティリリースの情報がある場合は「security」を表示します。
+`,
+	HelpUpgrade: `  チャンネルの最新のリリースをインストールして切り替えます。
+
+      patch   現在のマイナーバージョンの最新のパッチリリース(デフォルト)
+      stable  最新の正式リリース
+      pre     beta、rcを含めた最新のリリース
+
+  -pruneを指定すると切り替え後に以前のバージョンを削除します。
+  既に最新の場合は終了コード7で終了します。
+
+      golin upgrade
+      golin upgrade -channel stable -prune
+`,
+	HelpClean: `  golang.org/dlがルート外に残したファイルを削除します。
+  $GOPATH/bin/go{version}のコマンド、~/sdk/go{version}のディレクトリ、
+  モジュールキャッシュのgolang.org/dlが対象です。
+  golinが作成したファイル(.golin.jsonに記録)のみを削除します。
+  golang.org/dlでインストールしたバージョンのgo{version}、~/sdk/go{version}で
+  記録のないものは表示のみ行い、削除しません。
+  -nの場合は削除せずに表示のみ行います。
+
+      golin clean -n
+      golin clean
+`,
+	HelpDoctor: `  シンボリックリンクのバージョンを確認します。
+  リリース日、サポート状況、新しいパッチリリース、
+  標準ライブラリの既知の脆弱性、リリースノートを表示します。
+  サポート中のバージョンは最新の2つのマイナーバージョンです。
+  問題が見つかった場合はエラーで終了します。
+
+  -vulndbにはオフラインで利用するGoの脆弱性データベース(OSVのJSON)を指定します。
+  ミラーのURL(index/modules.json)、ミラーのコピー、JSONを置いたディレクトリ、
+  JSONファイルが指定できます。デフォルトは環境変数GOVULNDBです。
+
+      golin doctor
+      golin doctor -vulndb ~/vulndb
+`,
+	HelpShim: `  シムモードはGOROOTのシンボリックリンクの代わりの切り替え方法です。
+  goとgofmtのシムがGOROOTを設定して指定のバージョンのコマンドを実行する為、
+  リンクの権限が不要で、GOROOTをキャッシュするツールでも古いGOROOTになりません。
+  バージョンは実行ごとに以下の順に決定します。
+
+      $GOLIN_VERSION      環境変数
+      .go-version         カレントまたは上の階層のファイル
+      golin shim default  グローバルのデフォルト(ルートの.golin-version)
+      シンボリックリンク  golin switchのバージョン
+
+  マイナーバージョン(例: 1.21)の場合はインストール済みの最新のパッチリリースを利用します。
+
+      install  golinをgo、gofmtとして-dir(デフォルトは{root}/.shims)にコピー
+      default  グローバルのデフォルトを設定(バージョンなしの場合は削除)
+      pin      カレントディレクトリに.go-versionを作成
+      which    決定したSDKとバージョンの指定元を表示
+
+      golin shim install
+      golin shim pin 1.21
+      GOLIN_VERSION=1.20.8 go version
+`,
+	HelpToolchain: `  Go 1.21以降のgoコマンドはGOTOOLCHAIN、go.modのtoolchainにより
+  別のツールチェインを golang.org/toolchain@v0.0.1-go{version}.{GOOS}-{GOARCH}
+  としてモジュールキャッシュにダウンロードします。
+
+      list    モジュールキャッシュのこのプラットフォームのツールチェインを表示
+      import  ツールチェイン(すべて、または指定のバージョン)を
+              再ダウンロードせずにルートにコピー
+
+  golin switch、upgradeもモジュールキャッシュに存在するバージョンは取り込みます。
+  -toolchain-localを指定すると切り替え時にSDKのgo.envにGOTOOLCHAIN=localを書き込み、
+  goコマンドがリンク先のSDKを使い続けるようにします。
+  指定せずに切り替えた場合は元の行に戻します。
+
+      golin toolchain list
+      golin toolchain import 1.22.0
+      golin -toolchain-local 1.22.0
+`,
+	HelpImport: `  既存のGoのディレクトリをVERSIONファイルのバージョンの名称でルートに取り込み、
+  マニフェストに記録します。以降はダウンロードを行いません。
+
+  -scanは既知の場所のSDKを探します: /usr/local/go、~/sdk/go1.x(golang.org/dl)、
+  Homebrew、apt(/usr/lib/go-1.x)、PATHのgoコマンドのGOROOT、
+  モジュールキャッシュのgolang.org/toolchain。
+  ルートに存在するバージョンは取り込みません。-nは取り込まずに表示します。
+
+  -modeは取り込みの方法です。
+
+      copy  SDKをコピー(デフォルト、元のSDKは残ります)
+      move  SDKをルートに移動
+      link  ルートにSDKへのシンボリックリンクを作成
+            (golin removeはリンクのみ削除します)
+
+  モジュールキャッシュのツールチェインは常にコピーします。
+
+      golin import /usr/local/go
+      golin import -mode move ~/sdk/go1.21.0
+      golin import -scan -n
+`,
+	HelpDev: `  現在開発中の最新バージョン(gotip)をビルドして更新します（少し時間がかかります。
+  devは前回のソースを残しておき、新しいコミットがある場合のみビルドを行います。
+  ビルドは別ディレクトリで行い、完了後に入れ替えます。
+`,
+	HelpSelfUpdate: `  実行中のOS、アーキテクチャのgolinの最新のリリースをダウンロードし、
+  リリースのSHA256SUMSで検証してから実行ファイルを置き換えます。
+  実行ファイルと同じディレクトリに書き出してから入れ替える為、
+  書き込み途中の状態になることはありません。
+
+  -release-urlにはGitHubのリリースAPIと同じ形式(tag_name、assets)の
+  JSONを返すURLを指定します(ローカルのファイルサーバ等)。
+
+      golin self-update
+      sudo golin self-update
+`,
+	HelpVersion: `  golinのバージョンを表示します。
+`,
+	HelpCompletion: `  シェルの補完スクリプトを表示します。
+  コマンド、インストール済みのバージョン、インストール可能なバージョンを補完します。
+
+      bash       source <(golin completion bash)
+      zsh        golin completion zsh > "${fpath[1]}/_golin"
+      fish       golin completion fish > ~/.config/fish/completions/golin.fish
+      powershell golin completion powershell | Out-String | Invoke-Expression
+`,
+	HelpHelp: `  golin、またはコマンドの使い方を表示します。
+`,
+
+	FlagLink:                 "シンボリックリンクの名称",
+	FlagLinkDir:              "シンボリックリンクを作成するディレクトリ(デフォルトはルート)",
+	FlagGOROOT:               "環境変数の代わりに利用するGOROOT(sudoで実行する場合等)",
+	FlagToolchainLocal:       "切り替え時にSDKのgo.envにGOTOOLCHAIN=localを書き込む(Go 1.21以降)",
+	FlagAllowMissingChecksum: "チェックサムが公開されていないアーカイブも検証せずにインストールする",
+	FlagVerbose:              "デバッグログを表示",
+	FlagQuiet:                "警告とエラーのみ表示",
+	FlagLogFormat:            "ログの出力形式(textかjson)",
+	FlagLang:                 "表示する言語(enかja)",
+
+	FlagInstalled:      "インストール済みのバージョンのみ",
+	FlagAvailable:      "インストールしていないバージョンのみ",
+	FlagStable:         "正式リリースのみ",
+	FlagPre:            "betaとrcのみ",
+	FlagMinor:          "マイナーバージョンで絞り込み(例: 1.21)",
+	FlagLatestPerMinor: "マイナーバージョンごとの最新のみ",
+
+	FlagRepair: "存在しない、内容が異なるファイルを展開し直す",
+
+	FlagChannel: "アップグレードのチャンネル(patch, stable, pre)",
+	FlagPrune:   "切り替え後に以前のバージョンを削除する",
+
+	FlagReleaseURL: "最新のリリースの位置(GitHubのリリースAPIの形式)",
+
+	FlagVulnDB: "Goの脆弱性データベース(パスまたはミラーのURL、デフォルトは$GOVULNDB)",
+
+	FlagDryRun: "削除せずに対象のファイルを表示する",
+
+	FlagScan:         "既知の場所のSDKを探して取り込む",
+	FlagImportMode:   "取り込みの方法(copy、moveまたはlink)",
+	FlagImportDryRun: "取り込まずに対象のSDKを表示",
+
+	FlagShimDir: "シムのディレクトリ(デフォルトは{root}/.shims)",
+}
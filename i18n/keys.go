@@ -0,0 +1,176 @@
+package i18n
+
+// メッセージのキー
+const (
+	//golinパッケージ
+	ConfirmRoot Key = "confirm_root" //GOROOTの上の階層に作成する確認(root,now,version,link,linkPath)
+	Setting     Key = "setting"      //インストール後の設定手順(link,version)
+	ConfirmSudo Key = "confirm_sudo" //リンクの作成のみ権限を昇格する確認(link,command)
+	Before      Key = "before"       //切り替え前のバージョン
+	After       Key = "after"        //切り替え後のバージョン
+
+	//golinコマンド
+	Usage                    Key = "usage"                      //コマンドの使い方
+	ExitStatus               Key = "exit_status"                //終了コードの説明
+	Error                    Key = "error"                      //エラーの表示(error)
+	Success                  Key = "success"                    //正常終了
+	RequiredCommand          Key = "required_command"           //コマンドの指定がない
+	RequiredPath             Key = "required_path"              //installのパスの指定がない
+	RequiredVersion          Key = "required_version"           //バージョンの指定がない
+	VerboseQuiet             Key = "verbose_quiet"              //-verboseと-quietの同時指定
+	UnknownLogFormat         Key = "unknown_log_format"         //ログの出力形式の誤り(format)
+	UnknownLang              Key = "unknown_lang"               //言語の誤り(lang)
+	DevelopmentVersion       Key = "development_version"        //開発版のgolin
+	EmptyVersion             Key = "empty_version"              //バージョン情報がない
+	CommandVersion           Key = "command_version"            //golinのバージョン(version,build,date,build)
+	CommandsTitle            Key = "commands_title"             //コマンド一覧の見出し
+	OptionsTitle             Key = "options_title"              //オプションの見出し
+	HelpHint                 Key = "help_hint"                  //golin help {command}の案内
+	CommandUsage             Key = "command_usage"              //コマンドの使い方(command args)
+	UnknownCommand           Key = "unknown_command"            //コマンドが不明(command)
+	DidYouMean               Key = "did_you_mean"               //似ているコマンドの候補(commands)
+	InvalidVersion           Key = "invalid_version"            //バージョンとして解析できない(version)
+	RequiredShell            Key = "required_shell"             //completionのシェルの指定がない
+	UnknownShell             Key = "unknown_shell"              //completionのシェルが不明(shell)
+	ConflictFlags            Key = "conflict_flags"             //同時に指定できないオプション(flag,flag)
+	InventoryProblems        Key = "inventory_problems"         //inventoryで問題が見つかった(count)
+	InventoryHeader          Key = "inventory_header"           //inventoryの表の見出し(タブ区切り)
+	ListHeader               Key = "list_header"                //listの表の見出し(タブ区切り)
+	VerifyResult             Key = "verify_result"              //verifyの結果(version,files,missing,modified,extra,source)
+	VerifyRepaired           Key = "verify_repaired"            //verifyで修復した(count)
+	VerifyDamaged            Key = "verify_damaged"             //verifyで問題が見つかった(version,count)
+	VerifyMissing            Key = "verify_missing"             //verifyで不足しているファイル(path)
+	VerifyModified           Key = "verify_modified"            //verifyで変更されているファイル(path)
+	VerifyExtra              Key = "verify_extra"               //verifyで追加されているファイル(path)
+	RepairedFile             Key = "repaired_file"              //verify -repairで修復したファイル(path)
+	SelfUpToDate             Key = "self_up_to_date"            //self-updateで最新(version)
+	SelfUpdated              Key = "self_updated"               //self-updateの結果(before,after,path)
+	WarnUnsupported          Key = "warn_unsupported"           //リンク先のバージョンのサポートが終了している(version,supported)
+	WarnVulns                Key = "warn_vulns"                 //リンク先のバージョンに既知の脆弱性がある(version,count)
+	DoctorVersion            Key = "doctor_version"             //doctorのバージョン(version,date)
+	DoctorSupport            Key = "doctor_support"             //doctorのサポート状況(status,supported)
+	DoctorUpdate             Key = "doctor_update"              //doctorで更新がある(latest)
+	DoctorUpToDate           Key = "doctor_up_to_date"          //doctorで最新
+	DoctorVulns              Key = "doctor_vulns"               //doctorの脆弱性の件数(count)
+	DoctorNoVulnDB           Key = "doctor_no_vulndb"           //doctorで脆弱性のデータベースの指定がない
+	DoctorNotes              Key = "doctor_notes"               //doctorのリリースノート(url)
+	DoctorProblems           Key = "doctor_problems"            //doctorで問題が見つかった(count)
+	Cleaned                  Key = "cleaned"                    //cleanで削除した(count)
+	CleanWrapper             Key = "clean_wrapper"              //cleanで削除する$GOPATH/bin/go{version}(path)
+	CleanSDK                 Key = "clean_sdk"                  //cleanで削除する~/sdk/go{version}(path)
+	CleanModule              Key = "clean_module"               //cleanで削除するモジュールキャッシュのgolang.org/dl(path)
+	CleanUntracked           Key = "clean_untracked"            //cleanで記録がない為削除しない(path)
+	CleanDryRun              Key = "clean_dry_run"              //clean -nで削除する件数(count)
+	NothingToClean           Key = "nothing_to_clean"           //cleanで削除するものがない
+	ShimNotInstalled         Key = "shim_not_installed"         //シムのバージョンがインストールされていない(version,source)
+	ShimInstalled            Key = "shim_installed"             //シムのインストール後の設定手順(dir)
+	RequiredShimCommand      Key = "required_shim_command"      //shimのサブコマンドの指定がない(commands)
+	WarnToolchain            Key = "warn_toolchain"             //goコマンドがツールチェインを切り替える可能性がある(version,GOTOOLCHAIN)
+	ToolchainImported        Key = "toolchain_imported"         //モジュールキャッシュから取り込んだ(version,module)
+	NoToolchains             Key = "no_toolchains"              //モジュールキャッシュにツールチェインがない
+	RequiredToolchainCommand Key = "required_toolchain_command" //toolchainのサブコマンドが不明(commands)
+	Imported                 Key = "imported"                   //importの結果(version,path,mode)
+	ImportCandidates         Key = "import_candidates"          //import -scan -nで取り込む件数(count)
+	ImportSkipped            Key = "import_skipped"             //ルートに存在する為取り込まなかった(path,error)
+	NoSDKsFound              Key = "no_sdks_found"              //import -scanでSDKが見つからない
+	RequiredImportPath       Key = "required_import_path"       //importのパスの指定がない
+	UnknownImportMode        Key = "unknown_import_mode"        //importの方法が不明(mode)
+	RequiredTool             Key = "required_tool"              //whichのツールの指定がない
+	NoUpdates                Key = "no_updates"                 //outdatedで更新がない
+	OutdatedHeader           Key = "outdated_header"            //outdatedの表の見出し(タブ区切り)
+	OutdatedSecurity         Key = "outdated_security"          //outdatedでセキュリティ修正を含む
+	UnknownChannel           Key = "unknown_channel"            //upgradeのチャンネルが不明(channel)
+	Upgraded                 Key = "upgraded"                   //upgradeの結果(before,after)
+	Pruned                   Key = "pruned"                     //upgradeで削除した(path)
+	CurrentInfo              Key = "current_info"               //現在のバージョン(version,path,link)
+	GoCommand                Key = "go_command"                 //PATHのgoコマンド(path)
+	GoNotFound               Key = "go_not_found"               //PATHにgoコマンドがない(bin)
+	GoNotInSDK               Key = "go_not_in_sdk"              //PATHのgoコマンドがSDKのものでない(bin)
+
+	//golinコマンドの一覧の説明
+	CmdInstall    Key = "cmd_install"
+	CmdSwitch     Key = "cmd_switch"
+	CmdList       Key = "cmd_list"
+	CmdRemove     Key = "cmd_remove"
+	CmdCurrent    Key = "cmd_current"
+	CmdWhich      Key = "cmd_which"
+	CmdInventory  Key = "cmd_inventory"
+	CmdVerify     Key = "cmd_verify"
+	CmdOutdated   Key = "cmd_outdated"
+	CmdUpgrade    Key = "cmd_upgrade"
+	CmdClean      Key = "cmd_clean"
+	CmdDoctor     Key = "cmd_doctor"
+	CmdShim       Key = "cmd_shim"
+	CmdToolchain  Key = "cmd_toolchain"
+	CmdImport     Key = "cmd_import"
+	CmdDev        Key = "cmd_dev"
+	CmdSelfUpdate Key = "cmd_self_update"
+	CmdVersion    Key = "cmd_version"
+	CmdCompletion Key = "cmd_completion"
+	CmdHelp       Key = "cmd_help"
+
+	//golin help {command}の説明
+	HelpInstall    Key = "help_install"
+	HelpSwitch     Key = "help_switch"
+	HelpList       Key = "help_list"
+	HelpRemove     Key = "help_remove"
+	HelpCurrent    Key = "help_current"
+	HelpWhich      Key = "help_which"
+	HelpInventory  Key = "help_inventory"
+	HelpVerify     Key = "help_verify"
+	HelpOutdated   Key = "help_outdated"
+	HelpUpgrade    Key = "help_upgrade"
+	HelpClean      Key = "help_clean"
+	HelpDoctor     Key = "help_doctor"
+	HelpShim       Key = "help_shim"
+	HelpToolchain  Key = "help_toolchain"
+	HelpImport     Key = "help_import"
+	HelpDev        Key = "help_dev"
+	HelpSelfUpdate Key = "help_self_update"
+	HelpVersion    Key = "help_version"
+	HelpCompletion Key = "help_completion"
+	HelpHelp       Key = "help_help"
+
+	//golinコマンドのオプション
+	FlagLink                 Key = "flag_link"
+	FlagLinkDir              Key = "flag_link_dir"
+	FlagGOROOT               Key = "flag_goroot"
+	FlagToolchainLocal       Key = "flag_toolchain_local"
+	FlagAllowMissingChecksum Key = "flag_allow_missing_checksum"
+	FlagVerbose              Key = "flag_verbose"
+	FlagQuiet                Key = "flag_quiet"
+	FlagLogFormat            Key = "flag_log_format"
+	FlagLang                 Key = "flag_lang"
+
+	//golin listのオプション
+	FlagInstalled      Key = "flag_installed"
+	FlagAvailable      Key = "flag_available"
+	FlagStable         Key = "flag_stable"
+	FlagPre            Key = "flag_pre"
+	FlagMinor          Key = "flag_minor"
+	FlagLatestPerMinor Key = "flag_latest_per_minor"
+
+	//golin verifyのオプション
+	FlagRepair Key = "flag_repair"
+
+	//golin upgradeのオプション
+	FlagChannel Key = "flag_channel"
+	FlagPrune   Key = "flag_prune"
+
+	//golin self-updateのオプション
+	FlagReleaseURL Key = "flag_release_url"
+
+	//golin list、doctorのオプション
+	FlagVulnDB Key = "flag_vulndb"
+
+	//golin cleanのオプション
+	FlagDryRun Key = "flag_dry_run"
+
+	//golin importのオプション
+	FlagScan         Key = "flag_scan"
+	FlagImportMode   Key = "flag_import_mode"
+	FlagImportDryRun Key = "flag_import_dry_run"
+
+	//golin shimのオプション
+	FlagShimDir Key = "flag_shim_dir"
+)
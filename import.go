@@ -0,0 +1,287 @@
+package golin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// ImportMode is how the existing SDK is taken into the root
+type ImportMode string
+
+const (
+	ImportCopy ImportMode = "copy" //ルートにコピー(元のSDKは残る)
+	ImportMove ImportMode = "move" //ルートに移動
+	ImportLink ImportMode = "link" //ルートにシンボリックリンクを作成して参照
+)
+
+// ImportModes is modes of Manager.ImportSDK
+var ImportModes = []ImportMode{ImportCopy, ImportMove, ImportLink}
+
+// Candidate is existing SDK found by Manager.ScanSDKs
+type Candidate struct {
+	Version   *Version
+	Path      string //SDKのディレクトリ(シンボリックリンクを解決済み)
+	Kind      string //見つかった場所の種類(system、golang.org/dl、homebrew、apt、toolchain等)
+	Installed bool   //ルートに同じバージョンが存在するか
+}
+
+// ImportResult is result of Manager.ImportSDK
+type ImportResult struct {
+	Version *Version
+	Source  string     //取り込んだSDK
+	Path    string     //ルート以下のディレクトリ
+	Mode    ImportMode //取り込みの方法(モジュールキャッシュは常にcopy)
+}
+
+// scanLocation is known location of the SDK
+type scanLocation struct {
+	pattern string //filepath.Globのパターン
+	kind    string
+}
+
+// scanLocations is known locations of the SDKs
+//
+// 公式のインストーラ、golang.org/dl、Homebrew、apt等のディレクトリです
+func scanLocations() []scanLocation {
+
+	list := make([]scanLocation, 0)
+	if home, err := os.UserHomeDir(); err == nil {
+		list = append(list, scanLocation{filepath.Join(home, "sdk", "go*"), "golang.org/dl"})
+	}
+
+	if runtime.GOOS == "windows" {
+		for _, env := range []string{"ProgramFiles", "ProgramFiles(x86)"} {
+			if dir := os.Getenv(env); dir != "" {
+				list = append(list, scanLocation{filepath.Join(dir, "Go"), "system"})
+			}
+		}
+		return list
+	}
+
+	return append(list,
+		scanLocation{"/usr/local/go", "system"},
+		scanLocation{"/usr/lib/go", "apt"},
+		scanLocation{"/usr/lib/go-*", "apt"},
+		scanLocation{"/usr/lib/golang", "dnf"},
+		scanLocation{"/usr/local/Cellar/go/*/libexec", "homebrew"},
+		scanLocation{"/opt/homebrew/Cellar/go/*/libexec", "homebrew"},
+		scanLocation{"/home/linuxbrew/.linuxbrew/Cellar/go/*/libexec", "homebrew"},
+		scanLocation{"/snap/go/current", "snap"},
+	)
+}
+
+//
+// sdkVersion is version of the Go tree
+//
+// goコマンドが存在し、VERSIONファイルの1行目(go1.21.0)を解析できる場合のみバージョンを返します
+// 開発版(devel)は取り込めない為エラーとします
+//
+func sdkVersion(path string) (*Version, error) {
+
+	bin := filepath.Join(path, "bin", exeName("go"))
+	if info, err := os.Stat(bin); err != nil || info.IsDir() {
+		return nil, xerrors.Errorf("go command not found: %s", bin)
+	}
+
+	b, err := os.ReadFile(filepath.Join(path, "VERSION"))
+	if err != nil {
+		return nil, xerrors.Errorf("os.ReadFile(): %w", err)
+	}
+	line := strings.TrimSpace(strings.SplitN(string(b), "\n", 2)[0])
+	if !strings.HasPrefix(line, "go") {
+		return nil, xerrors.Errorf("unsupported VERSION: %q", line)
+	}
+	v, err := Parse(line)
+	if err != nil {
+		return nil, xerrors.Errorf("Parse(): %w", err)
+	}
+	return v, nil
+}
+
+// resolveSDK is absolute path of the SDK with symbolic links resolved
+func resolveSDK(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", xerrors.Errorf("filepath.Abs(): %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", xerrors.Errorf("filepath.EvalSymlinks(): %w", err)
+	}
+	return resolved, nil
+}
+
+// managed is the path in the root
+func (m *Manager) managed(path string) bool {
+	root := m.root
+	if r, err := filepath.EvalSymlinks(root); err == nil {
+		root = r
+	}
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+//
+// ScanSDKs is find the existing SDKs
+//
+// 既知の場所(/usr/local/go、~/sdk/go1.x、Homebrew、apt等)、
+// PATHのgoコマンドのGOROOT、モジュールキャッシュのgolang.org/toolchainを探し、
+// 新しいバージョン順に返します
+// ルートで管理しているSDK(シンボリックリンクの参照を含む)は含みません
+//
+func (m *Manager) ScanSDKs(ctx context.Context) ([]*Candidate, error) {
+
+	if m.root == "" {
+		return nil, errNoRoot
+	}
+
+	locations := scanLocations()
+	if goroot := GetGoEnv("GOROOT"); goroot != "" {
+		locations = append(locations, scanLocation{goroot, "goroot"})
+	}
+
+	seen := make(map[string]bool)
+	list := make([]*Candidate, 0)
+	add := func(path, kind string) {
+		resolved, err := resolveSDK(path)
+		if err != nil || seen[resolved] || m.managed(resolved) {
+			return
+		}
+		seen[resolved] = true
+
+		v, err := sdkVersion(resolved)
+		if err != nil {
+			m.logger.Debug("skip", "path", path, "reason", err)
+			return
+		}
+		elm := Candidate{
+			Version: v,
+			Path:    resolved,
+			Kind:    kind,
+		}
+		if _, err := os.Stat(filepath.Join(m.root, v.String())); err == nil {
+			elm.Installed = true
+		}
+		list = append(list, &elm)
+	}
+
+	for _, loc := range locations {
+		if err := ctx.Err(); err != nil {
+			return nil, classify(ErrCancelled, xerrors.Errorf("scan canceled: %w", err))
+		}
+		matches, err := filepath.Glob(loc.pattern)
+		if err != nil {
+			return nil, xerrors.Errorf("filepath.Glob(): %w", err)
+		}
+		for _, p := range matches {
+			add(p, loc.kind)
+		}
+	}
+
+	toolchains, err := m.Toolchains(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("Toolchains(): %w", err)
+	}
+	for _, tc := range toolchains {
+		add(tc.Path, "toolchain")
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[j].Version.Less(list[i].Version)
+	})
+	return list, nil
+}
+
+//
+// ImportSDK is take the existing SDK into the root
+//
+// VERSIONファイルのバージョンの名称でルートにコピー、移動、
+// またはシンボリックリンクを作成し、マニフェストとファイルの一覧に記録します
+// 以降の切り替えでダウンロードを行わない為です
+// モジュールキャッシュのgolang.org/toolchainはキャッシュを壊さないように常にコピーします
+// 同じバージョンがルートに存在する場合はErrAlreadyInstalledを返します
+//
+func (m *Manager) ImportSDK(ctx context.Context, path string, mode ImportMode) (*ImportResult, error) {
+
+	valid := false
+	for _, elm := range ImportModes {
+		valid = valid || elm == mode
+	}
+	if !valid {
+		return nil, xerrors.Errorf("unknown import mode: %q", mode)
+	}
+
+	src, err := resolveSDK(path)
+	if err != nil {
+		return nil, classify(ErrVersionNotFound, xerrors.Errorf("resolveSDK(): %w", err))
+	}
+
+	v, err := sdkVersion(src)
+	if err != nil {
+		return nil, xerrors.Errorf("not a Go SDK %s: %w", path, err)
+	}
+
+	if m.root != "" && m.managed(src) {
+		return nil, xerrors.Errorf("%s is in the root: %w", path, ErrAlreadyInstalled)
+	}
+	root, err := m.getRoot(v.String())
+	if err != nil {
+		return nil, xerrors.Errorf("getRoot() error: %w", err)
+	}
+
+	dst := filepath.Join(root, v.String())
+	if _, err := os.Lstat(dst); err == nil {
+		return nil, xerrors.Errorf("go%s %s: %w", v, dst, ErrAlreadyInstalled)
+	}
+
+	entry := ManifestEntry{
+		Version: v.String(),
+		URL:     src,
+		Method:  MethodImport,
+	}
+	if _, ok := toolchainVersion(filepath.Base(src)); ok {
+		entry.URL = toolchainPath + "@" + strings.TrimPrefix(filepath.Base(src), "toolchain@")
+		entry.Method = MethodToolchain
+		mode = ImportCopy
+	}
+
+	m.logger.Info("import", "version", v.String(), "from", src, "to", dst, "mode", string(mode))
+
+	switch mode {
+	case ImportCopy:
+		err = m.copySDK(src, dst)
+	case ImportMove:
+		err = m.moveDir(src, dst)
+	case ImportLink:
+		err = os.Symlink(src, dst)
+		if err != nil {
+			err = classifyPermission(xerrors.Errorf("os.Symlink(): %w", err))
+		}
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("import %s: %w", src, err)
+	}
+	if mode == ImportMove {
+		//golang.org/dlの~/sdk/go{version}の記録
+		m.untrackArtifacts(src)
+	}
+
+	m.chown(dst)
+
+	m.recordFiles(v.String())
+	m.recordInstall(v.String(), &entry)
+
+	rtn := ImportResult{
+		Version: v,
+		Source:  src,
+		Path:    dst,
+		Mode:    mode,
+	}
+	return &rtn, nil
+}
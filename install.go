@@ -1,60 +1,115 @@
 package golin
 
 import (
-	"fmt"
-	"os"
+	"context"
 	"path/filepath"
-	"runtime"
 
-	"github.com/shizuokago/golin/v2/config"
 	"golang.org/x/xerrors"
 )
 
+//
+// Install is install Go to the path
+//
+// configの設定でManagerを作成し、Install()を行います
+//
 func Install(path string, ver string) error {
+	m, err := defaultManager(SetRoot(path))
+	if err != nil {
+		return xerrors.Errorf("defaultManager(): %w", err)
+	}
+	_, err = m.Install(context.Background(), ver)
+	return err
+}
+
+//
+// Install is install Go to the root
+//
+// バージョンの指定がない場合は最新のバージョンをダウンロードし、
+// リンクを作成します
+//
+func (m *Manager) Install(ctx context.Context, ver string) (*InstallResult, error) {
+
+	ver = canonicalVersion(ver)
+
+	path := m.root
+	if path == "" {
+		return nil, errNoRoot
+	}
 
 	//権限の確認
 	err := checkAuthorization(path)
 	if err != nil {
-		return xerrors.Errorf("Authorization error: %w", err)
+		return nil, xerrors.Errorf("Authorization error: %w", err)
 	}
 
 	var v *Version
 	// 指定がない場合、バージョンを取得
 	if ver == "" {
-		v, err = getLatestVersion()
+		v, err = m.getLatestVersion(ctx)
 		if err != nil {
-			return xerrors.Errorf("getLatestVersion() error: %w", err)
+			return nil, xerrors.Errorf("getLatestVersion() error: %w", err)
 		}
 	} else {
 		v = NewVersion(ver)
 	}
 
 	// そのバージョンをダウンロードし展開
-	url := fmt.Sprintf("%s/go%s.%s-%s.%s", config.GolangDownloadPage, v.String(), runtime.GOOS, runtime.GOARCH, getDownloadExt())
-
-	fmt.Println("Download Latest Version:", url)
-
-	dp := filepath.Join(path, v.String())
-	//作成
-	err = DecompressURL(url, dp)
+	dp, url, err := m.installArchive(ctx, path, v)
 	if err != nil {
-		return xerrors.Errorf("DecompressURL() error: %w", err)
+		return nil, err
 	}
 
-	//currentを作成
-	link, err := readyLink(path)
+	err = m.applyToolchain(dp)
 	if err != nil {
-		return xerrors.Errorf("readyLink() error: %w", err)
+		return nil, xerrors.Errorf("applyToolchain(): %w", err)
 	}
 
-	//シンボリックリンクを作成
-	err = os.Symlink(dp, link)
+	//currentを作成(必要な場合のみ昇格)
+	link, err := m.createLink(ctx, dp)
 	if err != nil {
-		return xerrors.Errorf("symlink: %w", err)
+		return nil, xerrors.Errorf("createLink() error: %w", err)
 	}
 
 	// 各OSに合わせた設定手順を表示
-	printSetting(link, v.String())
+	m.printSetting(link, v.String())
+
+	rtn := InstallResult{
+		Version: v,
+		Path:    dp,
+		Link:    link,
+		URL:     url,
+	}
+	return &rtn, nil
+}
+
+//
+// installArchive is download and decompress the archive to the root
+//
+// 展開したディレクトリとアーカイブのURLを返します
+// 入手元とファイルの一覧を記録します
+//
+func (m *Manager) installArchive(ctx context.Context, root string, v *Version) (string, string, error) {
+
+	url := m.source.ArchiveURL(v)
+
+	m.logger.Info("download archive", "version", v.String(), "url", url)
+
+	dp := filepath.Join(root, v.String())
+	//作成
+	digest, err := m.decompressURL(ctx, url, dp)
+	if err != nil {
+		return "", "", xerrors.Errorf("DecompressURL() error: %w", err)
+	}
+
+	m.chown(dp)
 
-	return nil
+	//入手元、ファイルの一覧を記録
+	m.recordFiles(v.String())
+	m.recordInstall(v.String(), &ManifestEntry{
+		Version: v.String(),
+		URL:     url,
+		SHA256:  digest,
+		Method:  MethodArchive,
+	})
+	return dp, url, nil
 }
@@ -0,0 +1,234 @@
+// Package golintest is hermetic test harness of golin
+//
+// httptestで偽のリリースサーバを起動し、
+// バージョンの一覧(golang/dlと同じ形式)と小さな偽のSDKのアーカイブ(zip、tar.gz)を返します
+// SDKのbin/goは「go version」と同じ形式でバージョンを表示するスクリプトです
+// Managerに設定することでインストール、切り替え、一覧をネットワークなしで実行できます
+package golintest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shizuokago/golin/v2"
+)
+
+// releaseDate is release date of the first version
+//
+// 2つ目以降のバージョンは1日ずつずらします
+var releaseDate = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Server is fake release server
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	versions []string
+	archives map[string][]byte //アーカイブのファイル名がキー
+	sums     map[string]string //公開するチェックサム(差し替え用)
+	requests []string
+}
+
+// NewServer is start fake release server
+//
+// 指定したバージョンを一覧に含め、それぞれのzipとtar.gzを返します
+// テストの終了時にサーバを停止します
+func NewServer(t testing.TB, versions ...string) *Server {
+
+	t.Helper()
+
+	s := Server{
+		versions: versions,
+		archives: make(map[string][]byte),
+		sums:     make(map[string]string),
+	}
+
+	for _, v := range versions {
+		for _, format := range []golin.CompressType{golin.CompressZip, golin.CompressTarGz} {
+			data, err := Archive(v, format)
+			if err != nil {
+				t.Fatalf("golintest: archive %s: %v", v, err)
+			}
+			s.archives[ArchiveName(v, format)] = data
+		}
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return &s
+}
+
+// Source is release location of the server
+func (s *Server) Source() *golin.Source {
+	return &golin.Source{
+		ListURL:     s.URL + "/",
+		DownloadURL: s.URL,
+		Archive:     true,
+	}
+}
+
+// Options is Manager options for the server
+//
+// rootをルートにし、出力は破棄、確認にはすべて「Y」で答えます
+func (s *Server) Options(root string) []golin.Option {
+	return []golin.Option{
+		golin.SetRoot(root),
+		golin.SetSource(s.Source()),
+		golin.SetOutput(io.Discard, io.Discard),
+		golin.SetProgress(false),
+		golin.SetPrompter(golin.NewPrompter(strings.NewReader(strings.Repeat("Y\n", 10)), io.Discard)),
+	}
+}
+
+// NewManager is Manager using the server
+func (s *Server) NewManager(t testing.TB, root string, opts ...golin.Option) *golin.Manager {
+	t.Helper()
+	m, err := golin.NewManager(append(s.Options(root), opts...)...)
+	if err != nil {
+		t.Fatalf("golintest: NewManager: %v", err)
+	}
+	return m
+}
+
+// SetChecksum is replace the published checksum of the version
+//
+// チェックサムの不一致を再現する場合に利用します
+// 空文字の場合は.sha256を公開しません(404)
+func (s *Server) SetChecksum(v, sum string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, format := range []golin.CompressType{golin.CompressZip, golin.CompressTarGz} {
+		s.sums[ArchiveName(v, format)] = sum
+	}
+}
+
+// Requests is requested paths
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.URL.Path)
+
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	if name == "" {
+		s.writeIndex(w)
+		return
+	}
+
+	if strings.HasSuffix(name, ".sha256") {
+		name = strings.TrimSuffix(name, ".sha256")
+		data, ok := s.archives[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		sum, ok := s.sums[name]
+		if !ok {
+			h := sha256.Sum256(data)
+			sum = hex.EncodeToString(h[:])
+		} else if sum == "" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, sum)
+		return
+	}
+
+	data, ok := s.archives[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Write(data)
+}
+
+// writeIndex is release index (GitHub golang/dl)
+func (s *Server) writeIndex(w io.Writer) {
+	fmt.Fprintln(w, "<html><body>")
+	for i, v := range s.versions {
+		date := releaseDate.AddDate(0, 0, i)
+		fmt.Fprintf(w, `<div class="Box-row"><a class="js-navigation-open" href="/golang/dl/tree/master/go%s">go%s</a>`+
+			`<relative-time datetime="%s"></relative-time></div>`+"\n", v, v, date.Format(time.RFC3339))
+	}
+	fmt.Fprintln(w, "</body></html>")
+}
+
+// ArchiveName is archive file name of the version
+func ArchiveName(v string, format golin.CompressType) string {
+	ext := "tar.gz"
+	if format == golin.CompressZip {
+		ext = "zip"
+	}
+	return fmt.Sprintf("go%s.%s-%s.%s", v, runtime.GOOS, runtime.GOARCH, ext)
+}
+
+// StubGo is bin/go of the fake SDK
+//
+// 「go version」と同じ形式でバージョンを表示します
+func StubGo(v string) string {
+	return fmt.Sprintf("#!/bin/sh\necho \"go version go%s %s/%s\"\n", v, runtime.GOOS, runtime.GOARCH)
+}
+
+// Archive is fake SDK archive
+//
+// go/VERSION、go/bin/go、go/bin/gofmt、go/pkg/tool/{GOOS_GOARCH}/vetを含みます
+func Archive(v string, format golin.CompressType) ([]byte, error) {
+
+	dir, err := os.MkdirTemp("", "golintest-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	exe := ""
+	if runtime.GOOS == "windows" {
+		exe = ".exe"
+	}
+
+	files := map[string]string{
+		"VERSION":         fmt.Sprintf("go%s\ntime %s\n", v, releaseDate.Format(time.RFC3339)),
+		"bin/go" + exe:    StubGo(v),
+		"bin/gofmt" + exe: "#!/bin/sh\n",
+		filepath.Join("pkg", "tool", runtime.GOOS+"_"+runtime.GOARCH, "vet"+exe): "#!/bin/sh\n",
+	}
+	for name, data := range files {
+		fn := filepath.Join(dir, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			return nil, err
+		}
+		err = os.WriteFile(fn, []byte(data), 0755)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	err = golin.Compress(&buf, &golin.CompressOptions{
+		Format:  format,
+		BaseDir: dir,
+		Prefix:  "go",
+	}, ".")
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
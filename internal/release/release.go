@@ -0,0 +1,304 @@
+// Package release is reproducible release builder of golin
+//
+// golinコマンドを各OS、アーキテクチャ向けにクロスコンパイルし、
+// 同じソースから同じバイト列になるZIP、SHA256SUMS、マニフェストを作成します
+package release
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	ChecksumFile   = "SHA256SUMS"      //チェックサムファイル(sha256sumの形式)
+	ManifestFile   = "manifest.json"   //リリースのマニフェスト
+	DefaultPackage = "./v2/_cmd/golin" //golinコマンドのパッケージ(モジュールのルートから)
+)
+
+// Target is GOOS/GOARCH
+type Target struct {
+	GOOS   string `json:"goos"`
+	GOARCH string `json:"goarch"`
+}
+
+func (t Target) String() string {
+	return t.GOOS + "/" + t.GOARCH
+}
+
+// ParseTarget is parse "GOOS/GOARCH"
+func ParseTarget(s string) (Target, error) {
+	slice := strings.Split(s, "/")
+	if len(slice) != 2 || slice[0] == "" || slice[1] == "" {
+		return Target{}, xerrors.Errorf("invalid target: %q (GOOS/GOARCH)", s)
+	}
+	return Target{GOOS: slice[0], GOARCH: slice[1]}, nil
+}
+
+// DefaultTargets is release platforms
+var DefaultTargets = []Target{
+	{"darwin", "amd64"},
+	{"darwin", "arm64"},
+	{"windows", "386"},
+	{"windows", "amd64"},
+	{"linux", "386"},
+	{"linux", "amd64"},
+}
+
+// AssetName is release zip name of the target
+//
+// golin self-updateが参照する名称です
+func AssetName(t Target) string {
+	return fmt.Sprintf("golin_%s_%s.zip", t.GOOS, t.GOARCH)
+}
+
+// Config is release build setting
+//
+// Dateはldflagsに埋め込む日時とZIPのタイムスタンプに利用する為、
+// 再現性の為にコミットの日時等の固定の日時を指定します
+type Config struct {
+	Version  string    //バージョン(例: 2.0.1)
+	Revision string    //コミット
+	Date     time.Time //ビルド日時
+	Targets  []Target  //ビルドするOS、アーキテクチャ
+	Package  string    //golinコマンドのパッケージ
+	Dir      string    //go buildを実行するディレクトリ(モジュールのルート)
+	Output   string    //出力先のディレクトリ
+	Files    []string  //ZIPに追加するファイル(README.md等)
+	Go       string    //goコマンド
+	Stderr   io.Writer //go buildの出力
+}
+
+// Artifact is release zip
+type Artifact struct {
+	Name   string `json:"name"`
+	Target Target `json:"target"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest is release manifest
+type Manifest struct {
+	Version   string      `json:"version"`
+	Revision  string      `json:"revision"`
+	Date      time.Time   `json:"date"`
+	GoVersion string      `json:"go_version"`
+	Artifacts []*Artifact `json:"artifacts"`
+}
+
+// Build is build the release
+//
+// ターゲットごとに-trimpathでビルドしてZIPを作成し、
+// 出力先にSHA256SUMSとmanifest.jsonを書き出します
+func Build(ctx context.Context, conf *Config) (*Manifest, error) {
+
+	c := *conf
+	if c.Version == "" {
+		return nil, xerrors.Errorf("version is empty")
+	}
+	if c.Date.IsZero() {
+		return nil, xerrors.Errorf("date is empty")
+	}
+	if len(c.Targets) == 0 {
+		c.Targets = DefaultTargets
+	}
+	if c.Package == "" {
+		c.Package = DefaultPackage
+	}
+	if c.Go == "" {
+		c.Go = "go"
+	}
+	if c.Stderr == nil {
+		c.Stderr = os.Stderr
+	}
+	c.Date = c.Date.UTC().Truncate(time.Second)
+
+	err := os.MkdirAll(c.Output, 0755)
+	if err != nil {
+		return nil, xerrors.Errorf("os.MkdirAll(): %w", err)
+	}
+
+	work, err := os.MkdirTemp("", "golin-release-")
+	if err != nil {
+		return nil, xerrors.Errorf("os.MkdirTemp(): %w", err)
+	}
+	defer os.RemoveAll(work)
+
+	goVersion, err := exec.CommandContext(ctx, c.Go, "env", "GOVERSION").Output()
+	if err != nil {
+		return nil, xerrors.Errorf("go env GOVERSION: %w", err)
+	}
+
+	mf := Manifest{
+		Version:   c.Version,
+		Revision:  c.Revision,
+		Date:      c.Date,
+		GoVersion: strings.TrimSpace(string(goVersion)),
+		Artifacts: make([]*Artifact, 0, len(c.Targets)),
+	}
+
+	for _, t := range c.Targets {
+		art, err := c.build(ctx, work, t)
+		if err != nil {
+			return nil, xerrors.Errorf("build %s: %w", t, err)
+		}
+		mf.Artifacts = append(mf.Artifacts, art)
+	}
+
+	sort.Slice(mf.Artifacts, func(i, j int) bool {
+		return mf.Artifacts[i].Name < mf.Artifacts[j].Name
+	})
+
+	var sums bytes.Buffer
+	for _, art := range mf.Artifacts {
+		fmt.Fprintf(&sums, "%s  %s\n", art.SHA256, art.Name)
+	}
+	err = os.WriteFile(filepath.Join(c.Output, ChecksumFile), sums.Bytes(), 0644)
+	if err != nil {
+		return nil, xerrors.Errorf("write %s: %w", ChecksumFile, err)
+	}
+
+	b, err := json.MarshalIndent(&mf, "", "  ")
+	if err != nil {
+		return nil, xerrors.Errorf("json.MarshalIndent(): %w", err)
+	}
+	err = os.WriteFile(filepath.Join(c.Output, ManifestFile), append(b, '\n'), 0644)
+	if err != nil {
+		return nil, xerrors.Errorf("write %s: %w", ManifestFile, err)
+	}
+	return &mf, nil
+}
+
+// build is cross compile and compress the target
+func (c *Config) build(ctx context.Context, work string, t Target) (*Artifact, error) {
+
+	name := "golin"
+	if t.GOOS == "windows" {
+		name += ".exe"
+	}
+
+	dir := filepath.Join(work, t.GOOS+"_"+t.GOARCH)
+	bin := filepath.Join(dir, name)
+
+	ldflags := fmt.Sprintf("-s -w -X 'main.version=%s' -X 'main.revision=%s' -X 'main.date=%s' -X 'main.build=%s'",
+		c.Version, c.Revision, c.Date.Format(time.RFC1123Z), t)
+
+	cmd := exec.CommandContext(ctx, c.Go, "build", "-trimpath", "-buildvcs=false",
+		"-ldflags", ldflags, "-o", bin, c.Package)
+	cmd.Dir = c.Dir
+	cmd.Env = append(os.Environ(), "GOOS="+t.GOOS, "GOARCH="+t.GOARCH, "CGO_ENABLED=0", "GOFLAGS=")
+	cmd.Stdout = c.Stderr
+	cmd.Stderr = c.Stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, xerrors.Errorf("go build: %w", err)
+	}
+
+	files := []*File{{Name: name, Path: bin, Mode: 0755}}
+	for _, elm := range c.Files {
+		files = append(files, &File{Name: filepath.Base(elm), Path: elm, Mode: 0644})
+	}
+
+	art := Artifact{
+		Name:   AssetName(t),
+		Target: t,
+	}
+
+	f, err := os.Create(filepath.Join(c.Output, art.Name))
+	if err != nil {
+		return nil, xerrors.Errorf("os.Create(): %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	cw := &countWriter{w: io.MultiWriter(f, h)}
+	err = WriteZip(cw, c.Date, files)
+	if err != nil {
+		return nil, xerrors.Errorf("WriteZip(): %w", err)
+	}
+	err = f.Close()
+	if err != nil {
+		return nil, xerrors.Errorf("close %s: %w", art.Name, err)
+	}
+
+	art.Size = cw.n
+	art.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return &art, nil
+}
+
+// File is file in the release zip
+type File struct {
+	Name string      //ZIP内の名称
+	Path string      //ファイルのパス
+	Mode os.FileMode //パーミッション
+}
+
+// WriteZip is deterministic zip
+//
+// エントリを名前順に並べ、更新日時をmodに固定し、
+// パーミッションも指定したものだけにする為、
+// 同じ内容であれば常に同じZIPになります
+func WriteZip(w io.Writer, mod time.Time, files []*File) error {
+
+	sorted := make([]*File, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	zw := zip.NewWriter(w)
+	for _, elm := range sorted {
+		data, err := os.ReadFile(elm.Path)
+		if err != nil {
+			return xerrors.Errorf("os.ReadFile(): %w", err)
+		}
+
+		h := zip.FileHeader{
+			Name:     filepath.ToSlash(elm.Name),
+			Method:   zip.Deflate,
+			Modified: mod.UTC(),
+		}
+		h.SetMode(elm.Mode)
+
+		fw, err := zw.CreateHeader(&h)
+		if err != nil {
+			return xerrors.Errorf("zip CreateHeader(): %w", err)
+		}
+		_, err = fw.Write(data)
+		if err != nil {
+			return xerrors.Errorf("zip Write(): %w", err)
+		}
+	}
+
+	err := zw.Close()
+	if err != nil {
+		return xerrors.Errorf("zip Close(): %w", err)
+	}
+	return nil
+}
+
+// countWriter is written size counter
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
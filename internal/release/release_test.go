@@ -0,0 +1,73 @@
+package release_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shizuokago/golin/v2/internal/release"
+)
+
+func TestWriteZip(t *testing.T) {
+
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "golin")
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(bin, []byte("binary"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(readme, []byte("readme"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	mod := time.Date(2021, 7, 28, 0, 0, 0, 0, time.UTC)
+	files := []*release.File{
+		{Name: "golin", Path: bin, Mode: 0755},
+		{Name: "README.md", Path: readme, Mode: 0644},
+	}
+
+	var first bytes.Buffer
+	if err := release.WriteZip(&first, mod, files); err != nil {
+		t.Fatalf("WriteZip error[%v]", err)
+	}
+
+	//ファイルの更新日時、指定順が異なっても同じZIPになる
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(bin, later, later)
+	var second bytes.Buffer
+	if err := release.WriteZip(&second, mod, []*release.File{files[1], files[0]}); err != nil {
+		t.Fatalf("WriteZip error[%v]", err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Errorf("WriteZip is not reproducible")
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(first.Bytes()), int64(first.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zr.File) != 2 || zr.File[0].Name != "README.md" || zr.File[1].Name != "golin" {
+		t.Fatalf("WriteZip entries %v", zr.File)
+	}
+	if zr.File[1].Mode().Perm() != 0755 || !zr.File[1].Modified.Equal(mod) {
+		t.Errorf("WriteZip header mode[%v] modified[%v]", zr.File[1].Mode(), zr.File[1].Modified)
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	target, err := release.ParseTarget("linux/amd64")
+	if err != nil || target.GOOS != "linux" || target.GOARCH != "amd64" {
+		t.Errorf("ParseTarget [%v] error[%v]", target, err)
+	}
+	if release.AssetName(target) != "golin_linux_amd64.zip" {
+		t.Errorf("AssetName [%s]", release.AssetName(target))
+	}
+	for _, elm := range []string{"linux", "linux/", "/amd64", "a/b/c"} {
+		if _, err := release.ParseTarget(elm); err == nil {
+			t.Errorf("ParseTarget(%q) want error", elm)
+		}
+	}
+}
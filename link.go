@@ -0,0 +1,114 @@
+package golin
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/shizuokago/golin/v2/i18n"
+	"golang.org/x/xerrors"
+)
+
+// defaultSudo is command of the privilege escalation
+var defaultSudo = []string{"sudo"}
+
+// linkSuffix is suffix of the symbolic link moved aside while switching
+const linkSuffix = ".golin-old"
+
+//
+// DefaultRoot is per-user SDK root
+//
+// ルートの指定もGOROOTもない場合のルートです
+// $XDG_DATA_HOME/golin(未設定の場合は~/.local/share/golin)、
+// Windowsの場合は%LOCALAPPDATA%\golinです
+//
+func DefaultRoot() string {
+	dir := dataHome()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "golin")
+}
+
+//
+// createLink is create the symbolic link to the path
+//
+// リンクの位置に書き込めない場合(システムが所有するディレクトリ等)は
+// 確認したうえでリンクの作成のみをsudoで行います
+// ダウンロード、展開は権限を昇格せずに行う為、SDKはユーザのルートに置きます
+//
+func (m *Manager) createLink(ctx context.Context, path string) (string, error) {
+
+	link, old, err := m.readyLink()
+	if err == nil {
+		err = os.Symlink(path, link)
+		if err != nil {
+			m.restoreLink(link, old)
+		}
+	}
+	if err == nil {
+		if old != "" {
+			os.Remove(old)
+		}
+		m.chown(link)
+		return link, nil
+	}
+
+	if !errors.Is(err, fs.ErrPermission) || !m.canEscalate() {
+		return "", classifyPermission(xerrors.Errorf("symlink: %w", err))
+	}
+
+	//既存のリンクは「ln -sfn」が置き換える為、拒否、失敗した場合もそのまま残る
+	link = m.linkPath()
+	err = m.escalateLink(ctx, path, link)
+	if err != nil {
+		return "", xerrors.Errorf("escalateLink(): %w", err)
+	}
+	return link, nil
+}
+
+// canEscalate is privilege escalation is available
+//
+// Windows、root、昇格のコマンドの指定がない場合は昇格しません
+func (m *Manager) canEscalate() bool {
+	return len(m.sudo) > 0 && runtime.GOOS != "windows" && os.Geteuid() != 0
+}
+
+//
+// escalateLink is create the symbolic link with sudo
+//
+// 実行するコマンドを表示して確認し、「ln -sfn」のみを昇格して実行します
+// sudoがパスワードを端末から読み込めるように、別のプロセスグループにはしません
+// (バックグラウンドのプロセスグループが端末を読むとSIGTTINで停止する為)
+// キャンセル時はexec.CommandContextの既定の動作でsudoを終了させます
+//
+func (m *Manager) escalateLink(ctx context.Context, path, link string) error {
+
+	args := append(append([]string{}, m.sudo[1:]...), "ln", "-sfn", path, link)
+	line := strings.Join(append([]string{m.sudo[0]}, args...), " ")
+
+	ok, err := m.prompter.Confirm(m.msg.Sprintf(i18n.ConfirmSudo, link, line))
+	if err != nil {
+		return xerrors.Errorf("prompt: %w", err)
+	}
+	if !ok {
+		return &Error{Kind: ErrCancelled}
+	}
+
+	m.logger.Info("escalate", "command", line)
+	cmd := exec.CommandContext(ctx, m.sudo[0], args...)
+	cmd.Stdin = os.Stdin
+	err = m.runCmd(ctx, cmd)
+	if err != nil {
+		if errors.Is(err, ErrCancelled) {
+			return err
+		}
+		return classify(ErrPermission, xerrors.Errorf("%s: %w", line, err))
+	}
+	return nil
+}
@@ -1,51 +1,362 @@
 package golin
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"text/tabwriter"
+
+	"github.com/shizuokago/golin/v2/i18n"
+	"golang.org/x/xerrors"
 )
 
 //
 // PrintGoVersionList is download list printing
 //
 // インストール可能なバージョンリストを元に並び替えを行い表示します
-// 存在するバージョンには「*」を表示します
+// リンク先のバージョンには「*」を表示します
 //
 func PrintGoVersionList() error {
 
-	verList, err := createVersionList()
+	m, err := defaultManager()
+	if err != nil {
+		return xerrors.Errorf("defaultManager(): %w", err)
+	}
+
+	list, err := m.List(context.Background())
 	if err != nil {
 		return err
 	}
 
-	parent := filepath.Dir(os.Getenv("GOROOT"))
-	gb := filepath.Join(parent, "*")
+	SetDiskSize(list)
+	m.PrintList(m.stdout, list)
+	return nil
+}
+
+// 一覧の日付の書式
+const (
+	installDateFormat = "2006-01-02 15:04"
+	releaseDateFormat = "2006-01-02"
+)
+
+//
+// PrintList is version list printing
+//
+// バージョン、インストール日時、サイズ、リリース日を列で表示します
+// リンク先のバージョンには「*」を表示します
+// 見出しは環境変数の言語で表示します(Manager.PrintListはSetLanguageの言語)
+//
+func PrintList(w io.Writer, list []*ListEntry) {
+	printList(w, i18n.NewPrinter(i18n.Detect("")), list)
+}
+
+//
+// PrintList is version list printing with the language of the Manager
+//
+func (m *Manager) PrintList(w io.Writer, list []*ListEntry) {
+	printList(w, m.msg, list)
+}
+
+func printList(w io.Writer, msg *i18n.Printer, list []*ListEntry) {
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, msg.Sprintf(i18n.ListHeader))
+
+	for _, elm := range list {
+		mark := " "
+		if elm.Current {
+			mark = "*"
+		}
 
-	matches, err := filepath.Glob(gb)
+		installed := "-"
+		if elm.Installed && !elm.InstallDate.IsZero() {
+			installed = elm.InstallDate.Local().Format(installDateFormat)
+		}
+
+		size := "-"
+		if elm.Installed && elm.Size > 0 {
+			size = formatSize(elm.Size)
+		}
+
+		released := "-"
+		if !elm.ReleaseDate.IsZero() {
+			released = elm.ReleaseDate.Local().Format(releaseDateFormat)
+		}
+
+		fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s\n", mark, elm.Version, installed, size, released)
+	}
+	tw.Flush()
+}
+
+// formatSize is human readable size
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+//
+// List is version list
+//
+// インストール可能なバージョンとインストール済みのバージョンのリストを返します
+// ダウンロードできなくなったバージョンもインストール済みであれば含めます
+//
+func (m *Manager) List(ctx context.Context) ([]*ListEntry, error) {
+
+	releases, err := m.fetchReleases(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	installed, err := m.Installed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := make(map[string]*ListEntry, len(installed))
+	for _, elm := range installed {
+		exists[elm.Version.String()] = elm
+	}
+
+	list := make([]*ListEntry, 0, len(releases)+len(installed))
+	for _, r := range releases {
+		v := r.version.String()
+		entry, ok := exists[v]
+		if ok {
+			delete(exists, v)
+			//インストール済みのバージョンにも一覧のメタデータを設定
+			entry.Version.date = r.version.date
+			entry.Version.support = r.version.support
+		} else {
+			entry = &ListEntry{Version: r.version}
+		}
+		entry.Available = true
+		entry.ReleaseDate = r.date
+		entry.Security = r.security
+		list = append(list, entry)
+	}
+
+	//ダウンロードできなくなったバージョン
+	versions := make([]*Version, len(releases))
+	for i, r := range releases {
+		versions[i] = r.version
+	}
+	minors := supportedMinors(versions)
+	for _, elm := range exists {
+		setSupport(elm.Version, minors)
+		list = append(list, elm)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Version.Less(list[j].Version)
+	})
+	return list, nil
+}
+
+//
+// Installed is installed version list
+//
+// ルートに存在するバージョンのディレクトリを返します
+// シンボリックリンク、「.」「_」で始まる作業用のディレクトリ、
+// マニフェストに記録がなくバージョンとして解析できないディレクトリは除きます
+//
+func (m *Manager) Installed(ctx context.Context) ([]*ListEntry, error) {
+
+	if m.root == "" {
+		return nil, errNoRoot
 	}
 
-	exists := make([]string, len(matches))
-	for idx, ex := range matches {
-		wk := strings.Replace(ex, parent, "", 1)
-		exists[idx] = wk[1:]
+	entries, err := os.ReadDir(m.root)
+	//ユーザのルートは初回の切り替えまで存在しない
+	if errors.Is(err, fs.ErrNotExist) {
+		return []*ListEntry{}, nil
+	} else if err != nil {
+		return nil, classifyPermission(xerrors.Errorf("os.ReadDir(): %w", err))
 	}
 
-	//op := getOption()
-	for _, ver := range verList {
-		v := ver.String()
+	current := ""
+	if cur, err := m.Current(ctx); err == nil {
+		current = cur.Path
+	}
 
-		for _, ex := range exists {
-			if ex == v {
-				v = v + strings.Repeat(" ", 20-len(v)) + "*"
-				break
+	//マニフェストに記録がある場合はそのバージョン、日時を利用する
+	sdks := make(map[string]*ManifestEntry)
+	if mf, err := m.Manifest(); err == nil {
+		sdks = mf.SDKs
+	} else {
+		m.logger.Warn("load manifest", "error", err)
+	}
+
+	list := make([]*ListEntry, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			continue
+		}
+		//golin import -mode linkで参照しているSDKはシンボリックリンク
+		if !entry.IsDir() {
+			info, err := os.Stat(filepath.Join(m.root, name))
+			if entry.Type()&fs.ModeSymlink == 0 || name == m.linkName || err != nil || !info.IsDir() {
+				continue
 			}
 		}
-		fmt.Println(v)
+		record, tracked := sdks[name]
+		if !tracked && name != CompileSDK && NewVersion(name).Mean() == MeanError {
+			continue
+		}
+
+		path := filepath.Join(m.root, name)
+		elm := ListEntry{
+			Version:   NewVersion(name),
+			Installed: true,
+			Current:   path == current,
+			Path:      path,
+		}
+		if tracked {
+			elm.Version = NewVersion(record.Version)
+			elm.InstallDate = record.InstalledAt
+		} else if info, err := entry.Info(); err == nil {
+			elm.InstallDate = info.ModTime()
+		}
+		list = append(list, &elm)
 	}
 
-	return nil
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version.Less(list[j].Version)
+	})
+	return list, nil
+}
+
+//
+// ListFilter is filter of the version list
+//
+// Installed       インストール済みのバージョンのみ
+// Available       インストールしていないダウンロード可能なバージョンのみ
+// Stable          正式リリースのみ
+// Pre             beta、rcのみ
+// Minor           マイナーバージョンの指定(例: 1.21)
+// LatestPerMinor  マイナーバージョンごとの最新のみ
+//
+type ListFilter struct {
+	Installed      bool
+	Available      bool
+	Stable         bool
+	Pre            bool
+	Minor          string
+	LatestPerMinor bool
+}
+
+//
+// Apply is filtered version list
+//
+// 条件に一致するバージョンを並び順を保って返します
+//
+func (f *ListFilter) Apply(list []*ListEntry) ([]*ListEntry, error) {
+
+	var minor *Version
+	if f.Minor != "" {
+		minor = NewVersion(f.Minor)
+		if minor.Mean() == MeanError {
+			return nil, xerrors.Errorf("invalid minor version: %q", f.Minor)
+		}
+	}
+
+	rtn := make([]*ListEntry, 0, len(list))
+	for _, elm := range list {
+		v := elm.Version
+		switch {
+		case f.Installed && !elm.Installed:
+			continue
+		case f.Available && (elm.Installed || !elm.Available):
+			continue
+		case f.Stable && v.mean != Major:
+			continue
+		case f.Pre && !v.Prerelease():
+			continue
+		case minor != nil && (v.mean == MeanError || v.major != minor.major || v.minor != minor.minor):
+			continue
+		}
+		rtn = append(rtn, elm)
+	}
+
+	if !f.LatestPerMinor {
+		return rtn, nil
+	}
+
+	//マイナーバージョンごとに最大のものを残す
+	latest := make(map[[2]int]*ListEntry)
+	for _, elm := range rtn {
+		v := elm.Version
+		if v.mean == MeanError {
+			continue
+		}
+		key := [2]int{v.major, v.minor}
+		if now, ok := latest[key]; !ok || now.Version.Less(v) {
+			latest[key] = elm
+		}
+	}
+
+	filtered := make([]*ListEntry, 0, len(latest))
+	for _, elm := range rtn {
+		v := elm.Version
+		if v.mean != MeanError && latest[[2]int{v.major, v.minor}] == elm {
+			filtered = append(filtered, elm)
+		}
+	}
+	return filtered, nil
+}
+
+//
+// SetDiskSize is disk size of the installed versions
+//
+// インストール済みのバージョンのディレクトリのサイズを設定します
+//
+func SetDiskSize(list []*ListEntry) {
+	for _, elm := range list {
+		if !elm.Installed || elm.Path == "" {
+			continue
+		}
+		elm.Size, _ = DiskSize(elm.Path)
+	}
+}
+
+//
+// DiskSize is total file size in the directory
+//
+// シンボリックリンクは辿らずにファイルのサイズを合計します
+//
+func DiskSize(path string) (int64, error) {
+
+	var size int64
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return size, xerrors.Errorf("filepath.WalkDir(): %w", err)
+	}
+	return size, nil
 }
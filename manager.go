@@ -0,0 +1,214 @@
+package golin
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/shizuokago/golin/v2/config"
+	"github.com/shizuokago/golin/v2/i18n"
+	"golang.org/x/xerrors"
+)
+
+// Manager is Go SDK manager
+//
+// ルートディレクトリ以下のGo SDKとシンボリックリンクを管理します
+// パッケージのグローバルな状態を持たない為、
+// 複数のManagerを同時に利用することができます
+type Manager struct {
+	root     string
+	linkName string
+	//シンボリックリンクを作成するディレクトリ(未指定の場合はルート)
+	linkDir string
+	//リンクの作成に利用する権限昇格のコマンド(空の場合は昇格しない)
+	sudo []string
+	//sudoで実行した場合の実行したユーザ(作成したファイルの所有者を戻す)
+	owner *sudoUser
+	//GOROOTの指定(SetGOROOT、未指定の場合は環境変数GOROOT)
+	goroot    string
+	gorootSet bool
+	source    *Source
+	//golin自身のリリース(self-update)
+	releaseURL string
+	//開発版のリポジトリとブランチ
+	sourceRepository string
+	sourceBranch     string
+
+	client   *http.Client
+	logger   *slog.Logger
+	stdout   io.Writer
+	stderr   io.Writer
+	prompter Prompter
+	msg      *i18n.Printer
+
+	//進捗バーの表示(未指定の場合は出力先が端末の場合のみ)
+	progress    bool
+	progressSet bool
+
+	//脆弱性のデータベース(未指定の場合は環境変数GOVULNDB)
+	vulnDB     string
+	vulns      *VulnDB
+	vulnLoaded bool
+
+	//GOROOTからルートを決定した場合、切り替え時に確認を行う
+	confirm bool
+	//切り替え前のgoコマンドのバージョン
+	goVersion *Version
+	//切り替え時にSDKのgo.envにGOTOOLCHAIN=localを書き込む
+	toolchainLocal bool
+	//チェックサムが公開されていないアーカイブを検証せずに展開する
+	allowMissingChecksum bool
+}
+
+// NewManager is create Manager
+//
+// ルートの指定がない場合は環境変数GOROOTの上の階層をルートにします
+// GOROOTも存在しない場合はユーザのルート(DefaultRoot)を利用します
+func NewManager(opts ...Option) (*Manager, error) {
+
+	m := Manager{
+		linkName:         config.DefaultLinkName,
+		source:           DefaultSource(),
+		releaseURL:       config.GolinReleaseURL,
+		sourceRepository: config.GoSourceRepository,
+		sourceBranch:     config.GoSourceBranch,
+		client:           http.DefaultClient,
+		logger:           slog.New(slog.DiscardHandler),
+		stdout:           os.Stdout,
+		stderr:           os.Stderr,
+		msg:              i18n.NewPrinter(i18n.Detect("")),
+		vulnDB:           os.Getenv("GOVULNDB"),
+		sudo:             defaultSudo,
+		owner:            lookupSudoUser(),
+	}
+
+	for _, opt := range opts {
+		err := opt(&m)
+		if err != nil {
+			return nil, xerrors.Errorf("manager option error: %w", err)
+		}
+	}
+
+	if m.prompter == nil {
+		m.prompter = NewPrompter(os.Stdin, m.stdout)
+	}
+
+	if !m.progressSet {
+		m.progress = isTerminal(m.stdout)
+	}
+
+	if m.root == "" {
+		goroot := m.getGOROOT()
+		if goroot != "" {
+			m.root = filepath.Dir(goroot)
+			//リンク名でない場合はGOROOTの上の階層に作成してよいか確認する
+			m.confirm = filepath.Base(goroot) != m.linkName
+		} else if m.owner != nil {
+			//sudoの場合は実行したユーザのルート
+			m.root = m.owner.userRoot()
+		} else {
+			m.root = DefaultRoot()
+		}
+	} else {
+		root, err := filepath.Abs(m.root)
+		if err != nil {
+			return nil, xerrors.Errorf("filepath.Abs(): %w", err)
+		}
+		m.root = root
+	}
+
+	if m.linkDir != "" {
+		dir, err := filepath.Abs(m.linkDir)
+		if err != nil {
+			return nil, xerrors.Errorf("filepath.Abs(): %w", err)
+		}
+		m.linkDir = dir
+	}
+
+	if m.owner != nil {
+		m.logger.Debug("sudo", "user", m.owner.name, "uid", m.owner.uid, "home", m.owner.home)
+	}
+	m.logger.Debug("manager", "root", m.root, "link", m.linkPath())
+	return &m, nil
+}
+
+// defaultManager is Manager of the package functions
+//
+// パッケージの関数はconfigの設定からManagerを作成して処理を行います
+func defaultManager(opts ...Option) (*Manager, error) {
+	conf := config.Get()
+	opts = append([]Option{SetLinkName(conf.LinkName)}, opts...)
+	return NewManager(opts...)
+}
+
+// Root is SDK root directory
+func (m *Manager) Root() string {
+	return m.root
+}
+
+// LinkName is symbolic link name
+func (m *Manager) LinkName() string {
+	return m.linkName
+}
+
+// linkPath is symbolic link path
+func (m *Manager) linkPath() string {
+	if m.linkDir != "" {
+		return filepath.Join(m.linkDir, m.linkName)
+	}
+	return filepath.Join(m.root, m.linkName)
+}
+
+// getGOROOT is GOROOT of the root
+//
+// SetGOROOT、環境変数GOROOT、sudoの場合は実行したユーザのgo envの設定の順に決定します
+func (m *Manager) getGOROOT() string {
+	if m.gorootSet {
+		return m.goroot
+	}
+	if goroot := os.Getenv("GOROOT"); goroot != "" {
+		return goroot
+	}
+	if m.owner != nil {
+		return userGOROOT(m.owner.home)
+	}
+	return ""
+}
+
+// getRoot is return Work Directory Path
+//
+// 処理対象のディレクトリを返します
+// GOROOTから決定したルートでGOROOTがリンクでない場合は
+// 作成してよいかをPrompterで確認します
+// ルートが決まらない場合はエラーとなります
+func (m *Manager) getRoot(ver string) (string, error) {
+
+	if m.root == "" {
+		return "", errNoRoot
+	}
+
+	if !m.confirm {
+		//ユーザのルートは初回に作成する
+		err := m.mkdirAll(m.root)
+		if err != nil {
+			return "", classifyPermission(xerrors.Errorf("os.MkdirAll(): %w", err))
+		}
+		return m.root, nil
+	}
+
+	goroot := m.getGOROOT()
+	now := filepath.Base(goroot)
+	msg := m.msg.Sprintf(i18n.ConfirmRoot, m.root, now, ver, m.linkName, m.linkPath())
+
+	ok, err := m.prompter.Confirm(msg)
+	if err != nil {
+		return "", xerrors.Errorf("prompt: %w", err)
+	}
+	if !ok {
+		return "", &Error{Kind: ErrCancelled}
+	}
+
+	return m.root, nil
+}
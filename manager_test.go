@@ -0,0 +1,1602 @@
+package golin_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shizuokago/golin/v2"
+	"github.com/shizuokago/golin/v2/i18n"
+	"github.com/shizuokago/golin/v2/internal/golintest"
+)
+
+// createFakeRoot is root with installed versions
+//
+// バージョンのディレクトリとリンクを持つルートを作成します
+func createFakeRoot(t *testing.T, current string, versions ...string) string {
+
+	root := t.TempDir()
+	for _, v := range versions {
+		err := os.MkdirAll(filepath.Join(root, v, "bin"), 0777)
+		if err != nil {
+			t.Fatalf("MkdirAll error[%v]", err)
+		}
+	}
+
+	if current != "" {
+		err := os.Symlink(filepath.Join(root, current), filepath.Join(root, "current"))
+		if err != nil {
+			t.Fatalf("Symlink error[%v]", err)
+		}
+	}
+	return root
+}
+
+func TestManagerCurrent(t *testing.T) {
+
+	root := createFakeRoot(t, "1.21.0", "1.20.1", "1.21.0")
+
+	m, err := golin.NewManager(golin.SetRoot(root), golin.SetOutput(io.Discard, io.Discard))
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+
+	cur, err := m.Current(context.Background())
+	if err != nil {
+		t.Fatalf("Current error[%v]", err)
+	}
+
+	if cur.Version.String() != "1.21.0" {
+		t.Errorf("Current version [%s] != [1.21.0]", cur.Version)
+	}
+	if cur.Path != filepath.Join(root, "1.21.0") {
+		t.Errorf("Current path [%s]", cur.Path)
+	}
+	if cur.Link != filepath.Join(root, "current") {
+		t.Errorf("Current link [%s]", cur.Link)
+	}
+	resolved, _ := filepath.EvalSymlinks(filepath.Join(root, "1.21.0"))
+	if cur.Resolved != resolved {
+		t.Errorf("Current resolved [%s] != [%s]", cur.Resolved, resolved)
+	}
+	//作成したSDKはPATHに存在しない
+	if cur.InPath {
+		t.Errorf("Current go command [%s] in SDK", cur.GoCommand)
+	}
+
+	m, err = golin.NewManager(golin.SetRoot(root), golin.SetLinkName("other"))
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+	_, err = m.Current(context.Background())
+	if err == nil {
+		t.Errorf("Current link not exist not error")
+	}
+}
+
+func TestManagerWhich(t *testing.T) {
+
+	root := createFakeRoot(t, "1.21.0", "1.20.1", "1.21.0")
+
+	exe := ""
+	if runtime.GOOS == "windows" {
+		exe = ".exe"
+	}
+	tool := filepath.Join(root, "1.20.1", "pkg", "tool", runtime.GOOS+"_"+runtime.GOARCH)
+	files := []string{
+		filepath.Join(root, "1.21.0", "bin", "gofmt"+exe),
+		filepath.Join(tool, "vet"+exe),
+	}
+	for _, f := range files {
+		os.MkdirAll(filepath.Dir(f), 0777)
+		err := os.WriteFile(f, nil, 0755)
+		if err != nil {
+			t.Fatalf("WriteFile error[%v]", err)
+		}
+	}
+
+	m, err := golin.NewManager(golin.SetRoot(root), golin.SetOutput(io.Discard, io.Discard))
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+
+	ctx := context.Background()
+	path, err := m.Which(ctx, "", "gofmt")
+	if err != nil {
+		t.Fatalf("Which current error[%v]", err)
+	}
+	if path != files[0] {
+		t.Errorf("Which current [%s] != [%s]", path, files[0])
+	}
+
+	path, err = m.Which(ctx, "1.20.1", "vet")
+	if err != nil {
+		t.Fatalf("Which version error[%v]", err)
+	}
+	if path != files[1] {
+		t.Errorf("Which version [%s] != [%s]", path, files[1])
+	}
+
+	_, err = m.Which(ctx, "1.20.1", "gofmt")
+	if err == nil {
+		t.Errorf("Which tool not exist not error")
+	}
+
+	_, err = m.Which(ctx, "1.19", "go")
+	if !errors.Is(err, golin.ErrVersionNotFound) {
+		t.Errorf("Which not installed version [%v]", err)
+	}
+}
+
+func TestManagerInstalled(t *testing.T) {
+
+	root := createFakeRoot(t, "1.21.0", "1.20.1", "1.21.0", "1.22rc1")
+	//バージョンでないディレクトリ、作業用のディレクトリ
+	for _, name := range []string{"tools", ".compile_sdk_src"} {
+		os.MkdirAll(filepath.Join(root, name), 0777)
+	}
+	err := os.WriteFile(filepath.Join(root, "1.21.0", "bin", "go"), []byte("go"), 0755)
+	if err != nil {
+		t.Fatalf("WriteFile error[%v]", err)
+	}
+
+	m, err := golin.NewManager(golin.SetRoot(root), golin.SetOutput(io.Discard, io.Discard))
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+
+	list, err := m.Installed(context.Background())
+	if err != nil {
+		t.Fatalf("Installed error[%v]", err)
+	}
+
+	want := []string{"1.20.1", "1.21.0", "1.22rc1"}
+	if len(list) != len(want) {
+		t.Fatalf("Installed length [%d] != [%d]", len(list), len(want))
+	}
+	for idx, elm := range list {
+		if elm.Version.String() != want[idx] {
+			t.Errorf("Installed[%d] [%s] != [%s]", idx, elm.Version, want[idx])
+		}
+		if elm.Current != (want[idx] == "1.21.0") {
+			t.Errorf("Installed[%d] current [%v]", idx, elm.Current)
+		}
+		if elm.InstallDate.IsZero() {
+			t.Errorf("Installed[%d] install date is zero", idx)
+		}
+	}
+
+	golin.SetDiskSize(list)
+	if list[1].Size != 2 {
+		t.Errorf("Installed size [%d] != [2]", list[1].Size)
+	}
+}
+
+func TestManagerPrintList(t *testing.T) {
+
+	list := []*golin.ListEntry{
+		{Version: golin.NewVersion("1.21.0"), Installed: true, Current: true},
+	}
+
+	for lang, want := range map[i18n.Lang]string{
+		i18n.English:  "VERSION",
+		i18n.Japanese: "バージョン",
+	} {
+		m, err := golin.NewManager(golin.SetRoot(t.TempDir()), golin.SetLanguage(lang))
+		if err != nil {
+			t.Fatalf("NewManager error[%v]", err)
+		}
+		var buf bytes.Buffer
+		m.PrintList(&buf, list)
+
+		lines := strings.Split(buf.String(), "\n")
+		if !strings.HasPrefix(strings.TrimSpace(lines[0]), want) {
+			t.Errorf("PrintList %s header [%s]", lang, lines[0])
+		}
+		if !strings.HasPrefix(lines[1], "* 1.21.0") {
+			t.Errorf("PrintList %s entry [%s]", lang, lines[1])
+		}
+	}
+}
+
+func TestListFilter(t *testing.T) {
+
+	entry := func(v string, installed, available bool) *golin.ListEntry {
+		return &golin.ListEntry{Version: golin.NewVersion(v), Installed: installed, Available: available}
+	}
+	list := []*golin.ListEntry{
+		entry("1.20.1", true, true),
+		entry("1.20.2", false, true),
+		entry("1.21.0", true, true),
+		entry("1.21.1", false, true),
+		entry("1.22rc1", false, true),
+		entry("1.23beta1", false, true),
+	}
+
+	tests := []struct {
+		name   string
+		filter golin.ListFilter
+		want   []string
+	}{
+		{"all", golin.ListFilter{}, []string{"1.20.1", "1.20.2", "1.21.0", "1.21.1", "1.22rc1", "1.23beta1"}},
+		{"installed", golin.ListFilter{Installed: true}, []string{"1.20.1", "1.21.0"}},
+		{"available", golin.ListFilter{Available: true}, []string{"1.20.2", "1.21.1", "1.22rc1", "1.23beta1"}},
+		{"stable", golin.ListFilter{Stable: true}, []string{"1.20.1", "1.20.2", "1.21.0", "1.21.1"}},
+		{"pre", golin.ListFilter{Pre: true}, []string{"1.22rc1", "1.23beta1"}},
+		{"minor", golin.ListFilter{Minor: "1.21"}, []string{"1.21.0", "1.21.1"}},
+		{"latest", golin.ListFilter{LatestPerMinor: true}, []string{"1.20.2", "1.21.1", "1.22rc1", "1.23beta1"}},
+		{"installed latest", golin.ListFilter{Installed: true, LatestPerMinor: true}, []string{"1.20.1", "1.21.0"}},
+	}
+
+	for _, test := range tests {
+		rtn, err := test.filter.Apply(list)
+		if err != nil {
+			t.Errorf("%s: Apply error[%v]", test.name, err)
+			continue
+		}
+		got := make([]string, len(rtn))
+		for idx, elm := range rtn {
+			got[idx] = elm.Version.String()
+		}
+		if strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("%s: [%v] != [%v]", test.name, got, test.want)
+		}
+	}
+
+	filter := golin.ListFilter{Minor: "abc"}
+	if _, err := filter.Apply(list); err == nil {
+		t.Errorf("invalid minor not error")
+	}
+}
+
+func TestManagerInventory(t *testing.T) {
+
+	serv := golintest.NewServer(t, "1.99.0")
+
+	root := createFakeRoot(t, "", "1.20.1", "1.21.0")
+	for name, data := range map[string]string{"bin/go": "", "VERSION": "go1.20.1\n"} {
+		err := os.WriteFile(filepath.Join(root, "1.20.1", name), []byte(data), 0755)
+		if err != nil {
+			t.Fatalf("WriteFile error[%v]", err)
+		}
+	}
+	m := serv.NewManager(t, root)
+
+	ctx := context.Background()
+	_, err := m.Install(ctx, "1.99.0")
+	if err != nil {
+		t.Fatalf("Install error[%v]", err)
+	}
+
+	mf, err := m.Manifest()
+	if err != nil {
+		t.Fatalf("Manifest error[%v]", err)
+	}
+	entry, ok := mf.SDKs["1.99.0"]
+	if !ok {
+		t.Fatalf("Manifest not recorded")
+	}
+	if entry.Method != golin.MethodArchive || entry.SHA256 == "" || entry.URL == "" {
+		t.Errorf("Manifest entry [%+v]", entry)
+	}
+	if entry.InstalledAt.IsZero() || entry.Platform != runtime.GOOS+"/"+runtime.GOARCH {
+		t.Errorf("Manifest entry [%+v]", entry)
+	}
+
+	//1.21.0は記録があるがgoコマンドがない、1.22.0は記録のみ
+	data := `{"format":1,"sdks":{
+		"1.99.0":{"version":"1.99.0","method":"archive"},
+		"1.21.0":{"version":"1.21.0","method":"archive"},
+		"1.22.0":{"version":"1.22.0","method":"golang.org/dl"}}}`
+	err = os.WriteFile(filepath.Join(root, ".golin.json"), []byte(data), 0666)
+	if err != nil {
+		t.Fatalf("WriteFile error[%v]", err)
+	}
+
+	list, err := m.Inventory(ctx)
+	if err != nil {
+		t.Fatalf("Inventory error[%v]", err)
+	}
+
+	want := map[string]golin.InventoryStatus{
+		"1.20.1": golin.StatusUntracked,
+		"1.21.0": golin.StatusCorrupted,
+		"1.22.0": golin.StatusMissing,
+		"1.99.0": golin.StatusOK,
+	}
+	if len(list) != len(want) {
+		t.Fatalf("Inventory length [%d] != [%d]", len(list), len(want))
+	}
+	for _, elm := range list {
+		if elm.Status != want[elm.Dir] {
+			t.Errorf("Inventory [%s] status [%s] != [%s] (%s)", elm.Dir, elm.Status, want[elm.Dir], elm.Reason)
+		}
+	}
+
+	_, err = m.Switch(ctx, "1.20.1")
+	if err != nil {
+		t.Fatalf("Switch error[%v]", err)
+	}
+	_, err = m.Remove(ctx, "1.99.0")
+	if err != nil {
+		t.Fatalf("Remove error[%v]", err)
+	}
+	mf, err = m.Manifest()
+	if err != nil {
+		t.Fatalf("Manifest error[%v]", err)
+	}
+	if _, ok := mf.SDKs["1.99.0"]; ok {
+		t.Errorf("Manifest removed version exists")
+	}
+}
+
+func TestManagerVerify(t *testing.T) {
+
+	serv := golintest.NewServer(t, "1.99.0")
+
+	root := t.TempDir()
+	m := serv.NewManager(t, root)
+
+	ctx := context.Background()
+	_, err := m.Install(ctx, "1.99.0")
+	if err != nil {
+		t.Fatalf("Install error[%v]", err)
+	}
+
+	rtn, err := m.Verify(ctx, "1.99.0", false)
+	if err != nil {
+		t.Fatalf("Verify error[%v]", err)
+	}
+	if !rtn.OK() || len(rtn.Extra) != 0 || rtn.Files != 4 {
+		t.Errorf("Verify installed [%+v]", rtn)
+	}
+
+	//破損させる
+	dir := filepath.Join(root, "1.99.0")
+	os.Remove(filepath.Join(dir, "bin", "go"))
+	os.WriteFile(filepath.Join(dir, "VERSION"), []byte("go1."), 0644)
+	os.WriteFile(filepath.Join(dir, "extra"), []byte("extra"), 0644)
+
+	for _, source := range []string{"recorded", "archive"} {
+
+		if source == "archive" {
+			//記録がない場合はアーカイブから比較する
+			os.RemoveAll(filepath.Join(root, ".golin_sums"))
+		}
+
+		rtn, err = m.Verify(ctx, "1.99.0", false)
+		if err != nil {
+			t.Fatalf("%s: Verify error[%v]", source, err)
+		}
+		if rtn.OK() {
+			t.Errorf("%s: Verify damaged OK", source)
+		}
+		if strings.Join(rtn.Missing, ",") != "bin/go" {
+			t.Errorf("%s: Verify missing %v", source, rtn.Missing)
+		}
+		if strings.Join(rtn.Modified, ",") != "VERSION" {
+			t.Errorf("%s: Verify modified %v", source, rtn.Modified)
+		}
+		if strings.Join(rtn.Extra, ",") != "extra" {
+			t.Errorf("%s: Verify extra %v", source, rtn.Extra)
+		}
+	}
+
+	rtn, err = m.Verify(ctx, "1.99.0", true)
+	if err != nil {
+		t.Fatalf("Verify repair error[%v]", err)
+	}
+	if strings.Join(rtn.Repaired, ",") != "VERSION,bin/go" {
+		t.Errorf("Verify repaired %v", rtn.Repaired)
+	}
+
+	rtn, err = m.Verify(ctx, "1.99.0", false)
+	if err != nil {
+		t.Fatalf("Verify error[%v]", err)
+	}
+	if !rtn.OK() {
+		t.Errorf("Verify repaired not OK [%+v]", rtn)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "extra")); err != nil {
+		t.Errorf("Verify repair removed extra file[%v]", err)
+	}
+
+	_, err = m.Verify(ctx, "1.98.0", false)
+	if !errors.Is(err, golin.ErrVersionNotFound) {
+		t.Errorf("Verify not installed [%v]", err)
+	}
+}
+
+func TestManagerOutdated(t *testing.T) {
+
+	serv := golintest.NewServer(t, "1.20.1", "1.20.2", "1.21rc1", "1.21.0", "1.21.1", "1.22rc1")
+
+	root := createFakeRoot(t, "1.21rc1", "1.20.1", "1.20.2", "1.21rc1", "1.22rc1")
+	m := serv.NewManager(t, root)
+
+	list, err := m.Outdated(context.Background())
+	if err != nil {
+		t.Fatalf("Outdated error[%v]", err)
+	}
+
+	//1.20は最新、1.22は正式リリースがない
+	if len(list) != 1 {
+		t.Fatalf("Outdated length [%d] != [1]", len(list))
+	}
+	up := list[0]
+	if up.Installed.String() != "1.21rc1" || up.Latest.String() != "1.21.1" || !up.Current {
+		t.Errorf("Outdated [%+v]", up)
+	}
+}
+
+func TestManagerUpgrade(t *testing.T) {
+
+	serv := golintest.NewServer(t, "1.20.1", "1.20.2", "1.21.0", "1.21.1", "1.22rc1")
+
+	root := createFakeRoot(t, "1.20.1", "1.20.1")
+	m := serv.NewManager(t, root)
+
+	ctx := context.Background()
+	rtn, err := m.Upgrade(ctx, golin.ChannelPatch, true)
+	if err != nil {
+		t.Fatalf("Upgrade error[%v]", err)
+	}
+	if rtn.Before.String() != "1.20.1" || rtn.After.String() != "1.20.2" {
+		t.Errorf("Upgrade [%s] -> [%s]", rtn.Before, rtn.After)
+	}
+	if rtn.Pruned != filepath.Join(root, "1.20.1") {
+		t.Errorf("Upgrade pruned [%s]", rtn.Pruned)
+	}
+	if _, err := os.Stat(filepath.Join(root, "1.20.1")); !os.IsNotExist(err) {
+		t.Errorf("Upgrade old version exists[%v]", err)
+	}
+
+	cur, err := m.Current(ctx)
+	if err != nil || cur.Version.String() != "1.20.2" {
+		t.Errorf("Upgrade current [%v] error[%v]", cur, err)
+	}
+
+	_, err = m.Upgrade(ctx, golin.ChannelPatch, false)
+	if !errors.Is(err, golin.ErrAlreadyCurrent) {
+		t.Errorf("Upgrade latest error[%v]", err)
+	}
+
+	rtn, err = m.Upgrade(ctx, golin.ChannelStable, false)
+	if err != nil {
+		t.Fatalf("Upgrade stable error[%v]", err)
+	}
+	if rtn.After.String() != "1.21.1" || rtn.Pruned != "" {
+		t.Errorf("Upgrade stable [%s] pruned[%s]", rtn.After, rtn.Pruned)
+	}
+}
+
+// releaseZip is golin release zip
+func releaseZip(t *testing.T, body string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	name := "golin"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	w, err := zw.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(body))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestManagerSelfUpdate(t *testing.T) {
+
+	asset := golin.ReleaseAsset()
+	archive := releaseZip(t, "new golin")
+	h := sha256.Sum256(archive)
+	sum := hex.EncodeToString(h[:])
+
+	var serv *httptest.Server
+	serv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest":
+			fmt.Fprintf(w, `{"tag_name":"v2.1.0","assets":[`+
+				`{"name":"%s","browser_download_url":"%s/%s"},`+
+				`{"name":"SHA256SUMS","browser_download_url":"%s/SHA256SUMS"}]}`,
+				asset, serv.URL, asset, serv.URL)
+		case "/" + asset:
+			w.Write(archive)
+		case "/SHA256SUMS":
+			fmt.Fprintf(w, "%s  %s\n", sum, asset)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer serv.Close()
+
+	exe := filepath.Join(t.TempDir(), "golin")
+	if err := os.WriteFile(exe, []byte("old golin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := golin.NewManager(
+		golin.SetReleaseURL(serv.URL+"/latest"),
+		golin.SetOutput(io.Discard, io.Discard),
+		golin.SetProgress(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	_, err = m.SelfUpdate(ctx, "2.1.0", exe)
+	if !errors.Is(err, golin.ErrAlreadyCurrent) {
+		t.Errorf("SelfUpdate latest error[%v]", err)
+	}
+
+	rtn, err := m.SelfUpdate(ctx, "2.0.1", exe)
+	if err != nil {
+		t.Fatalf("SelfUpdate error[%v]", err)
+	}
+	if rtn.After != "2.1.0" || rtn.SHA256 != sum {
+		t.Errorf("SelfUpdate result [%+v]", rtn)
+	}
+	b, err := os.ReadFile(exe)
+	if err != nil || string(b) != "new golin" {
+		t.Errorf("SelfUpdate executable [%s] error[%v]", b, err)
+	}
+
+	//チェックサムが一致しない場合は置き換えない
+	archive = releaseZip(t, "broken golin")
+	_, err = m.SelfUpdate(ctx, "", exe)
+	if !errors.Is(err, golin.ErrChecksumMismatch) {
+		t.Errorf("SelfUpdate checksum error[%v]", err)
+	}
+	b, err = os.ReadFile(exe)
+	if err != nil || string(b) != "new golin" {
+		t.Errorf("SelfUpdate replaced [%s] error[%v]", b, err)
+	}
+}
+
+func TestManagerRemove(t *testing.T) {
+
+	root := createFakeRoot(t, "1.21.0", "1.20.1", "1.21.0")
+
+	m, err := golin.NewManager(golin.SetRoot(root), golin.SetOutput(io.Discard, io.Discard))
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+
+	ctx := context.Background()
+	_, err = m.Remove(ctx, "1.21.0")
+	if err == nil {
+		t.Errorf("Remove current version not error")
+	}
+
+	_, err = m.Remove(ctx, "1.19")
+	if err == nil {
+		t.Errorf("Remove not installed version not error")
+	}
+
+	_, err = m.Remove(ctx, "current")
+	if err == nil {
+		t.Errorf("Remove link not error")
+	}
+
+	rtn, err := m.Remove(ctx, "1.20.1")
+	if err != nil {
+		t.Fatalf("Remove error[%v]", err)
+	}
+	if rtn.Path != filepath.Join(root, "1.20.1") {
+		t.Errorf("Remove path [%s]", rtn.Path)
+	}
+	if _, err := os.Stat(rtn.Path); !os.IsNotExist(err) {
+		t.Errorf("Removed directory exists[%v]", err)
+	}
+}
+
+func TestInstallCancel(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	//途中まで送信してキャンセルするサーバ
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gw := gzip.NewWriter(w)
+		tw := tar.NewWriter(gw)
+		tw.WriteHeader(&tar.Header{Name: "go/", Typeflag: tar.TypeDir, Mode: 0755})
+		tw.WriteHeader(&tar.Header{Name: "go/VERSION", Typeflag: tar.TypeReg, Mode: 0644, Size: 1024})
+		tw.Write([]byte("go1.99.0"))
+		tw.Flush()
+		gw.Flush()
+		w.(http.Flusher).Flush()
+
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer serv.Close()
+
+	root := t.TempDir()
+	m, err := golin.NewManager(
+		golin.SetRoot(root),
+		golin.SetSource(&golin.Source{ListURL: serv.URL, DownloadURL: serv.URL}),
+		golin.SetOutput(io.Discard, io.Discard))
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+
+	_, err = m.Install(ctx, "1.99.0")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Install canceled error[%v]", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "1.99.0")); !os.IsNotExist(err) {
+		t.Errorf("partial directory exists[%v]", err)
+	}
+}
+
+// testOSV is OSV entry of the stdlib vulnerability
+const testOSV = `{
+  "id": "GO-2023-0001",
+  "summary": "Test vulnerability in net/http",
+  "aliases": ["CVE-2023-0001"],
+  "affected": [{
+    "package": {"name": "stdlib", "ecosystem": "Go"},
+    "ranges": [{"type": "SEMVER", "events": [
+      {"introduced": "0"}, {"fixed": "1.20.2"},
+      {"introduced": "1.21.0-0"}, {"fixed": "1.21.1"}
+    ]}],
+    "ecosystem_specific": {"imports": [{"path": "net/http"}]}
+  }]
+}`
+
+func TestManagerDoctor(t *testing.T) {
+
+	serv := golintest.NewServer(t, "1.19.1", "1.20.1", "1.20.2", "1.21.0", "1.21.1")
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "GO-2023-0001.json"), []byte(testOSV), 0644)
+	if err != nil {
+		t.Fatalf("WriteFile error[%v]", err)
+	}
+
+	root := createFakeRoot(t, "1.19.1", "1.19.1", "1.21.0")
+	m := serv.NewManager(t, root, golin.SetVulnDB(dir))
+
+	ctx := context.Background()
+	rtn, err := m.Doctor(ctx)
+	if err != nil {
+		t.Fatalf("Doctor error[%v]", err)
+	}
+
+	if rtn.Support != golin.Unsupported {
+		t.Errorf("Doctor support [%s]", rtn.Support)
+	}
+	if strings.Join(rtn.SupportedMinors, ",") != "1.21,1.20" {
+		t.Errorf("Doctor supported minors %v", rtn.SupportedMinors)
+	}
+	if rtn.Current.Version.ReleaseDate().IsZero() {
+		t.Errorf("Doctor release date is zero")
+	}
+	if rtn.ReleaseNotes != "https://go.dev/doc/devel/release#go1.19.1" {
+		t.Errorf("Doctor release notes [%s]", rtn.ReleaseNotes)
+	}
+	if len(rtn.Vulns) != 1 || rtn.Vulns[0].ID != "GO-2023-0001" || rtn.Vulns[0].Fixed != "" {
+		t.Fatalf("Doctor vulns %+v", rtn.Vulns)
+	}
+	if rtn.Problems() != 2 {
+		t.Errorf("Doctor problems [%d] != [2]", rtn.Problems())
+	}
+
+	//同じマイナーバージョンで修正されたバージョン
+	vulns, ok := m.Vulnerabilities(ctx, golin.NewVersion("1.21.0"))
+	if !ok || len(vulns) != 1 || vulns[0].Fixed != "1.21.1" {
+		t.Errorf("Vulnerabilities(1.21.0) %v %+v", ok, vulns)
+	}
+	if vulns, _ := m.Vulnerabilities(ctx, golin.NewVersion("1.21.1")); len(vulns) != 0 {
+		t.Errorf("Vulnerabilities(1.21.1) %+v", vulns)
+	}
+
+	list, err := m.List(ctx)
+	if err != nil {
+		t.Fatalf("List error[%v]", err)
+	}
+	for _, elm := range list {
+		v := elm.Version.String()
+		security := v == "1.20.2" || v == "1.21.1"
+		if elm.Security != security {
+			t.Errorf("List %s security [%v]", v, elm.Security)
+		}
+		support := golin.Supported
+		if v == "1.19.1" {
+			support = golin.Unsupported
+		}
+		if elm.Version.Support() != support {
+			t.Errorf("List %s support [%s]", v, elm.Version.Support())
+		}
+	}
+}
+
+func TestManagerClean(t *testing.T) {
+
+	home := t.TempDir()
+	gopath := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("GOPATH", gopath)
+	t.Setenv("GOBIN", "")
+	t.Setenv("GOMODCACHE", filepath.Join(gopath, "pkg", "mod"))
+
+	exe := ""
+	if runtime.GOOS == "windows" {
+		exe = ".exe"
+	}
+	wrapper := filepath.Join(gopath, "bin", "go1.20.1"+exe)
+	other := filepath.Join(gopath, "bin", "go1.19.1"+exe)
+	sdk := filepath.Join(home, "sdk", "go1.20.1")
+	module := filepath.Join(gopath, "pkg", "mod", "golang.org", "dl@v0.0.0-20230101000000-000000000000")
+
+	for _, dir := range []string{filepath.Dir(wrapper), sdk, filepath.Join(module, "go1.20.1")} {
+		err := os.MkdirAll(dir, 0755)
+		if err != nil {
+			t.Fatalf("MkdirAll error[%v]", err)
+		}
+	}
+	for _, fn := range []string{wrapper, other, filepath.Join(module, "go1.20.1", "main.go")} {
+		err := os.WriteFile(fn, []byte("test"), 0444)
+		if err != nil {
+			t.Fatalf("WriteFile error[%v]", err)
+		}
+	}
+	//モジュールキャッシュは読み込み専用
+	os.Chmod(filepath.Join(module, "go1.20.1"), 0555)
+	os.Chmod(module, 0555)
+
+	root := createFakeRoot(t, "1.20.1", "1.20.1")
+	manifest := fmt.Sprintf(`{"format": 1,
+  "sdks": {"1.20.1": {"version": "1.20.1", "method": "golang.org/dl"}},
+  "artifacts": {%q: {"path": %q, "kind": "module"}, %q: {"path": %q, "kind": "wrapper"}, %q: {"path": %q, "kind": "wrapper"}}
+}`, module, module, wrapper, wrapper, "/nonexistent/go1.18", "/nonexistent/go1.18")
+	err := os.WriteFile(filepath.Join(root, ".golin.json"), []byte(manifest), 0644)
+	if err != nil {
+		t.Fatalf("WriteFile error[%v]", err)
+	}
+
+	m, err := golin.NewManager(golin.SetRoot(root), golin.SetOutput(io.Discard, io.Discard))
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+
+	ctx := context.Background()
+	rtn, err := m.Clean(ctx, true)
+	if err != nil {
+		t.Fatalf("Clean(dry run) error[%v]", err)
+	}
+	if len(rtn.Removed) != 2 {
+		t.Fatalf("Clean(dry run) removed %+v", rtn.Removed)
+	}
+	//記録のない~/sdk/go{version}は表示のみ
+	if len(rtn.Untracked) != 1 || rtn.Untracked[0].Path != sdk {
+		t.Errorf("Clean(dry run) untracked %+v", rtn.Untracked)
+	}
+	if _, err := os.Stat(wrapper); err != nil {
+		t.Errorf("dry run removed %s", wrapper)
+	}
+
+	rtn, err = m.Clean(ctx, false)
+	if err != nil {
+		t.Fatalf("Clean error[%v]", err)
+	}
+	if len(rtn.Removed) != 2 || len(rtn.Untracked) != 1 {
+		t.Errorf("Clean removed %+v untracked %+v", rtn.Removed, rtn.Untracked)
+	}
+	for _, p := range []string{wrapper, module} {
+		if _, err := os.Lstat(p); !os.IsNotExist(err) {
+			t.Errorf("%s is not removed", p)
+		}
+	}
+	//golinが作成していないコマンド、SDKは残す
+	for _, p := range []string{other, sdk} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("%s is removed", p)
+		}
+	}
+
+	mf, err := m.Manifest()
+	if err != nil {
+		t.Fatalf("Manifest error[%v]", err)
+	}
+	if len(mf.Artifacts) != 0 {
+		t.Errorf("Manifest artifacts %+v", mf.Artifacts)
+	}
+}
+
+func TestManagerCopyDir(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic link and mode are not supported")
+	}
+
+	src := filepath.Join(t.TempDir(), "go1.21.0")
+	mtime := time.Date(2023, 8, 8, 0, 0, 0, 0, time.UTC)
+
+	files := map[string]os.FileMode{
+		"VERSION":             0644,
+		"bin/go":              0755,
+		"pkg/tool/linux/vet":  0700,
+		"src/readonly/doc.go": 0444,
+	}
+	for name, mode := range files {
+		fn := filepath.Join(src, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			t.Fatalf("MkdirAll error[%v]", err)
+		}
+		err = os.WriteFile(fn, []byte(name), mode)
+		if err != nil {
+			t.Fatalf("WriteFile error[%v]", err)
+		}
+		os.Chmod(fn, mode)
+		os.Chtimes(fn, mtime, mtime)
+	}
+	err := os.Symlink(filepath.Join("..", "VERSION"), filepath.Join(src, "bin", "version"))
+	if err != nil {
+		t.Fatalf("Symlink error[%v]", err)
+	}
+	os.Chmod(filepath.Join(src, "src", "readonly"), 0555)
+	t.Cleanup(func() {
+		os.Chmod(filepath.Join(src, "src", "readonly"), 0755)
+	})
+
+	m, err := golin.NewManager(golin.SetOutput(io.Discard, io.Discard), golin.SetProgress(false))
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "go1.21.0")
+	err = m.CopyDir(src, dst)
+	if err != nil {
+		t.Fatalf("CopyDir error[%v]", err)
+	}
+	t.Cleanup(func() {
+		os.Chmod(filepath.Join(dst, "src", "readonly"), 0755)
+	})
+
+	for name, mode := range files {
+		fn := filepath.Join(dst, filepath.FromSlash(name))
+		info, err := os.Stat(fn)
+		if err != nil {
+			t.Errorf("Stat error[%v]", err)
+			continue
+		}
+		if info.Mode().Perm() != mode {
+			t.Errorf("%s mode [%v] != [%v]", name, info.Mode().Perm(), mode)
+		}
+		if !info.ModTime().Equal(mtime) {
+			t.Errorf("%s mtime [%v] != [%v]", name, info.ModTime(), mtime)
+		}
+		b, _ := os.ReadFile(fn)
+		if string(b) != name {
+			t.Errorf("%s data [%s]", name, b)
+		}
+	}
+
+	link, err := os.Readlink(filepath.Join(dst, "bin", "version"))
+	if err != nil || link != filepath.Join("..", "VERSION") {
+		t.Errorf("Readlink [%s] error[%v]", link, err)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "src", "readonly")); err != nil || info.Mode().Perm() != 0555 {
+		t.Errorf("directory mode [%v] error[%v]", info.Mode().Perm(), err)
+	}
+}
+
+func TestManagerSwitchSpelling(t *testing.T) {
+
+	isolateGoEnv(t)
+	serv := golintest.NewServer(t, "1.20.1")
+	root := t.TempDir()
+	m := serv.NewManager(t, root)
+
+	//開発版はgolang.org/dlのgotipでダウンロード済み
+	err := os.MkdirAll(filepath.Join(root, "tip", "bin"), 0755)
+	if err != nil {
+		t.Fatalf("MkdirAll error[%v]", err)
+	}
+
+	ctx := context.Background()
+	tests := []struct {
+		arg string
+		dir string
+	}{
+		{"go1.20.1", "1.20.1"},
+		{"gotip", "tip"},
+	}
+	for _, test := range tests {
+		rtn, err := m.Switch(ctx, test.arg)
+		if err != nil {
+			t.Fatalf("Switch(%s) error[%v]", test.arg, err)
+		}
+		want := filepath.Join(root, test.dir)
+		if rtn.Path != want || rtn.Version.String() != test.dir {
+			t.Errorf("Switch(%s) %s %s", test.arg, rtn.Path, rtn.Version)
+		}
+		if p, err := os.Readlink(rtn.Link); err != nil || p != want {
+			t.Errorf("Switch(%s) link [%s] error[%v]", test.arg, p, err)
+		}
+		if _, err := os.Lstat(filepath.Join(root, test.arg)); err == nil {
+			t.Errorf("Switch(%s) created %s", test.arg, test.arg)
+		}
+	}
+
+	if _, err := m.Which(ctx, "go1.20.1", "go"); err != nil {
+		t.Errorf("Which(go1.20.1) error[%v]", err)
+	}
+	if _, err := m.Remove(ctx, "go1.20.1"); err != nil {
+		t.Errorf("Remove(go1.20.1) error[%v]", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "1.20.1")); err == nil {
+		t.Errorf("Remove(go1.20.1) did not remove 1.20.1")
+	}
+}
+
+func TestManagerDevelopment(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("make.bash is not supported")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not found")
+	}
+
+	//ビルドしたSDKのbin/goを作成するだけのmake.bash
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=golin", "-c", "user.email=golin@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v error[%v] %s", args, err, out)
+		}
+	}
+	commit := func(msg string) {
+		t.Helper()
+		err := os.WriteFile(filepath.Join(repo, "README"), []byte(msg), 0644)
+		if err != nil {
+			t.Fatalf("WriteFile error[%v]", err)
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", msg)
+	}
+	err := os.MkdirAll(filepath.Join(repo, "src"), 0755)
+	if err != nil {
+		t.Fatalf("MkdirAll error[%v]", err)
+	}
+	script := "#!/bin/sh\nmkdir -p ../bin && printf '#!/bin/sh\\n' > ../bin/go && chmod 755 ../bin/go\n"
+	err = os.WriteFile(filepath.Join(repo, "src", "make.bash"), []byte(script), 0755)
+	if err != nil {
+		t.Fatalf("WriteFile error[%v]", err)
+	}
+	git("init", "-q", "-b", "master")
+	commit("first")
+
+	//実行中のgoコマンドのGOROOTは削除できずに残った退避先
+	root := t.TempDir()
+	kept := filepath.Join(root, ".compile_sdk_old")
+	err = os.MkdirAll(filepath.Join(kept, "bin"), 0755)
+	if err != nil {
+		t.Fatalf("MkdirAll error[%v]", err)
+	}
+	bin := t.TempDir()
+	fake := fmt.Sprintf("#!/bin/sh\nif [ \"$1 $2\" = \"env GOROOT\" ]; then echo %q; fi\n", kept)
+	err = os.WriteFile(filepath.Join(bin, "go"), []byte(fake), 0755)
+	if err != nil {
+		t.Fatalf("WriteFile error[%v]", err)
+	}
+	gitPath, _ := exec.LookPath("git")
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+filepath.Dir(gitPath)+string(os.PathListSeparator)+"/bin")
+
+	m, err := golin.NewManager(golin.SetRoot(root), golin.SetSourceRepository(repo, "master"),
+		golin.SetOutput(io.Discard, io.Discard), golin.SetProgress(false))
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+
+	ctx := context.Background()
+	path := filepath.Join(root, golin.CompileSDK)
+	revision := func() string {
+		b, _ := os.ReadFile(filepath.Join(path, ".golin_revision"))
+		return strings.TrimSpace(string(b))
+	}
+
+	_, err = m.Switch(ctx, golin.CompileSDK)
+	if err != nil {
+		t.Fatalf("Switch(build) error[%v]", err)
+	}
+	first := revision()
+	if _, err := os.Stat(filepath.Join(path, "bin", "go")); err != nil || first == "" {
+		t.Fatalf("development SDK is not built [%s] error[%v]", first, err)
+	}
+
+	//コミットが同じ場合はビルドしない
+	marker := filepath.Join(path, "marker")
+	err = os.WriteFile(marker, nil, 0644)
+	if err != nil {
+		t.Fatalf("WriteFile error[%v]", err)
+	}
+	_, err = m.Switch(ctx, golin.CompileSDK)
+	if err != nil {
+		t.Fatalf("Switch(unchanged) error[%v]", err)
+	}
+	if _, err := os.Stat(marker); err != nil || revision() != first {
+		t.Errorf("unchanged revision is rebuilt [%s] error[%v]", revision(), err)
+	}
+
+	//コミットが変わった場合はビルドして入れ替える(2回続けて)
+	for _, msg := range []string{"second", "third"} {
+		commit(msg)
+		_, err = m.Switch(ctx, golin.CompileSDK)
+		if err != nil {
+			t.Fatalf("Switch(%s) error[%v]", msg, err)
+		}
+		if _, err := os.Stat(marker); err == nil || revision() == first {
+			t.Errorf("Switch(%s) is not rebuilt [%s]", msg, revision())
+		}
+		if _, err := os.Stat(filepath.Join(path, "bin", "go")); err != nil {
+			t.Errorf("Switch(%s) bin/go error[%v]", msg, err)
+		}
+		//退避したSDKは削除し、実行中のものは残す
+		list, _ := filepath.Glob(filepath.Join(root, ".compile_sdk_old*"))
+		if len(list) != 1 || list[0] != kept {
+			t.Errorf("Switch(%s) old SDKs %v", msg, list)
+		}
+	}
+}
+
+func TestManagerLinkDir(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("sudo is not supported")
+	}
+
+	serv := golintest.NewServer(t, "1.20.1")
+	root := t.TempDir()
+	linkDir := t.TempDir()
+
+	m := serv.NewManager(t, root, golin.SetLinkDir(linkDir))
+	rtn, err := m.Switch(context.Background(), "1.20.1")
+	if err != nil {
+		t.Fatalf("Switch error[%v]", err)
+	}
+	if rtn.Link != filepath.Join(linkDir, "current") {
+		t.Errorf("Switch link [%s]", rtn.Link)
+	}
+	if p, err := os.Readlink(rtn.Link); err != nil || p != filepath.Join(root, "1.20.1") {
+		t.Errorf("Readlink [%s] error[%v]", p, err)
+	}
+
+	cur, err := m.Current(context.Background())
+	if err != nil || cur.Path != filepath.Join(root, "1.20.1") {
+		t.Errorf("Current %+v error[%v]", cur, err)
+	}
+}
+
+func TestManagerEscalateLink(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("sudo is not supported")
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("root can create the link without sudo")
+	}
+
+	serv := golintest.NewServer(t, "1.20.1")
+	root := t.TempDir()
+	linkDir := t.TempDir()
+
+	//書き込み権限を付けてから実行する偽のsudo
+	sudo := filepath.Join(t.TempDir(), "sudo")
+	script := fmt.Sprintf("#!/bin/sh\nchmod 755 %q && exec \"$@\"\n", linkDir)
+	err := os.WriteFile(sudo, []byte(script), 0755)
+	if err != nil {
+		t.Fatalf("WriteFile error[%v]", err)
+	}
+	//切り替え前のリンク
+	prev := filepath.Join(root, "1.19.0")
+	err = os.Symlink(prev, filepath.Join(linkDir, "prev"))
+	if err != nil {
+		t.Fatalf("Symlink error[%v]", err)
+	}
+	os.Chmod(linkDir, 0555)
+	t.Cleanup(func() {
+		os.Chmod(linkDir, 0755)
+	})
+
+	//拒否した場合はキャンセル
+	m := serv.NewManager(t, root, golin.SetLinkDir(linkDir), golin.SetLinkName("prev"), golin.SetSudo(sudo),
+		golin.SetPrompter(golin.NewPrompter(strings.NewReader("n\n"), io.Discard)))
+	_, err = m.Switch(context.Background(), "1.20.1")
+	if !errors.Is(err, golin.ErrCancelled) {
+		t.Errorf("Switch error[%v] is not ErrCancelled", err)
+	}
+	//元のリンクは残る
+	if p, err := os.Readlink(filepath.Join(linkDir, "prev")); err != nil || p != prev {
+		t.Errorf("Readlink [%s] error[%v]", p, err)
+	}
+	//展開は昇格せずに行う
+	if _, err := os.Stat(filepath.Join(root, "1.20.1", "VERSION")); err != nil {
+		t.Errorf("SDK is not extracted[%v]", err)
+	}
+
+	//昇格しない場合は権限エラー
+	m = serv.NewManager(t, root, golin.SetLinkDir(linkDir), golin.SetSudo())
+	_, err = m.Switch(context.Background(), "1.20.1")
+	if !errors.Is(err, golin.ErrPermission) {
+		t.Errorf("Switch error[%v] is not ErrPermission", err)
+	}
+
+	m = serv.NewManager(t, root, golin.SetLinkDir(linkDir), golin.SetSudo(sudo))
+	rtn, err := m.Switch(context.Background(), "1.20.1")
+	if err != nil {
+		t.Fatalf("Switch error[%v]", err)
+	}
+	if p, err := os.Readlink(rtn.Link); err != nil || p != filepath.Join(root, "1.20.1") {
+		t.Errorf("Readlink [%s] error[%v]", p, err)
+	}
+}
+
+func TestManagerSetGOROOT(t *testing.T) {
+
+	root := createFakeRoot(t, "1.21.0", "1.21.0")
+	t.Setenv("GOROOT", "")
+
+	m, err := golin.NewManager(golin.SetGOROOT(filepath.Join(root, "current")),
+		golin.SetOutput(io.Discard, io.Discard))
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+	if m.Root() != root {
+		t.Errorf("Root [%s] != [%s]", m.Root(), root)
+	}
+
+	cur, err := m.Current(context.Background())
+	if err != nil {
+		t.Fatalf("Current error[%v]", err)
+	}
+	if cur.Version.String() != "1.21.0" {
+		t.Errorf("Current version [%s]", cur.Version)
+	}
+
+	_, err = golin.NewManager(golin.SetGOROOT(""))
+	if err == nil {
+		t.Errorf("SetGOROOT(\"\") is not error")
+	}
+}
+
+func TestManagerResolve(t *testing.T) {
+
+	root := createFakeRoot(t, "1.20.8", "1.20.8", "1.21.0", "1.21.3")
+	m, err := golin.NewManager(golin.SetRoot(root), golin.SetOutput(io.Discard, io.Discard))
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+
+	ctx := context.Background()
+	project := t.TempDir()
+	sub := filepath.Join(project, "cmd", "app")
+	err = os.MkdirAll(sub, 0755)
+	if err != nil {
+		t.Fatalf("MkdirAll error[%v]", err)
+	}
+
+	t.Setenv(golin.ShimEnv, "")
+	check := func(name, version string, source golin.ShimSource) {
+		t.Helper()
+		res, err := m.Resolve(ctx, sub)
+		if err != nil {
+			t.Fatalf("%s: Resolve error[%v]", name, err)
+		}
+		if res.Version != version || res.Source != source || res.GOROOT != filepath.Join(root, version) {
+			t.Errorf("%s: Resolve %+v", name, res)
+		}
+	}
+
+	//リンク先
+	check("link", "1.20.8", golin.ShimFromLink)
+
+	//グローバルのデフォルト(マイナーバージョンは最新のパッチ)
+	err = m.SetDefault("1.21")
+	if err != nil {
+		t.Fatalf("SetDefault error[%v]", err)
+	}
+	check("default", "1.21.3", golin.ShimFromDefault)
+
+	//上の階層の.go-version
+	_, err = m.Pin(project, "go1.21.0")
+	if err != nil {
+		t.Fatalf("Pin error[%v]", err)
+	}
+	check("pin", "1.21.0", golin.ShimFromPin)
+
+	//環境変数
+	t.Setenv(golin.ShimEnv, "1.20.8")
+	check("env", "1.20.8", golin.ShimFromEnv)
+
+	t.Setenv(golin.ShimEnv, "1.19")
+	_, err = m.Resolve(ctx, sub)
+	if !errors.Is(err, golin.ErrVersionNotFound) {
+		t.Errorf("Resolve error[%v] is not ErrVersionNotFound", err)
+	}
+}
+
+func TestManagerShimCommand(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("stub go is a shell script")
+	}
+
+	serv := golintest.NewServer(t, "1.20.8", "1.21.0")
+	root := t.TempDir()
+	m := serv.NewManager(t, root)
+
+	ctx := context.Background()
+	for _, v := range []string{"1.21.0", "1.20.8"} {
+		if _, err := m.Switch(ctx, v); err != nil {
+			t.Fatalf("Switch error[%v]", err)
+		}
+	}
+
+	shims, err := m.InstallShims("", "/bin/sh")
+	if err != nil {
+		t.Fatalf("InstallShims error[%v]", err)
+	}
+	if len(shims) != 2 || filepath.Dir(shims[0]) != m.ShimDir() {
+		t.Errorf("InstallShims %v", shims)
+	}
+
+	t.Setenv(golin.ShimEnv, "1.21.0")
+	cmd, res, err := m.ShimCommand(ctx, "go", []string{"version"}, t.TempDir())
+	if err != nil {
+		t.Fatalf("ShimCommand error[%v]", err)
+	}
+	if res.Version != "1.21.0" {
+		t.Errorf("ShimCommand version [%s]", res.Version)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Output error[%v]", err)
+	}
+	if !strings.Contains(string(out), "go1.21.0") {
+		t.Errorf("go version [%s]", out)
+	}
+
+	goroot := ""
+	for _, elm := range cmd.Env {
+		if strings.HasPrefix(elm, "GOROOT=") {
+			goroot = strings.TrimPrefix(elm, "GOROOT=")
+		}
+	}
+	if goroot != filepath.Join(root, "1.21.0") {
+		t.Errorf("GOROOT [%s]", goroot)
+	}
+}
+
+func TestManagerToolchain(t *testing.T) {
+
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	t.Setenv("GOTOOLCHAIN", "")
+
+	exe := ""
+	if runtime.GOOS == "windows" {
+		exe = ".exe"
+	}
+	mod := fmt.Sprintf("v0.0.1-go1.21.0.%s-%s", runtime.GOOS, runtime.GOARCH)
+	tc := filepath.Join(cache, "golang.org", "toolchain@"+mod)
+	files := map[string]string{
+		"bin/go" + exe: golintest.StubGo("1.21.0"),
+		"VERSION":      "go1.21.0\ntime 2023-08-08T15:00:00Z\n",
+		"go.env":       "GOPROXY=https://proxy.golang.org,direct\nGOTOOLCHAIN=auto\n",
+	}
+	for name, body := range files {
+		fn := filepath.Join(tc, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			t.Fatalf("MkdirAll error[%v]", err)
+		}
+		err = os.WriteFile(fn, []byte(body), 0555)
+		if err != nil {
+			t.Fatalf("WriteFile error[%v]", err)
+		}
+	}
+	//モジュールキャッシュは読み込み専用
+	os.Chmod(filepath.Join(tc, "bin"), 0555)
+	os.Chmod(tc, 0555)
+	t.Cleanup(func() {
+		os.Chmod(tc, 0755)
+		os.Chmod(filepath.Join(tc, "bin"), 0755)
+	})
+
+	//サーバにはバージョンが存在しない(ダウンロードした場合はエラー)
+	serv := golintest.NewServer(t)
+	root := createFakeRoot(t, "", "1.20.1")
+	m := serv.NewManager(t, root, golin.SetToolchainLocal(true))
+
+	ctx := context.Background()
+	list, err := m.Toolchains(ctx)
+	if err != nil {
+		t.Fatalf("Toolchains error[%v]", err)
+	}
+	if len(list) != 1 || list[0].Version.String() != "1.21.0" || list[0].Installed {
+		t.Fatalf("Toolchains %+v", list)
+	}
+	if list[0].Module != "golang.org/toolchain@"+mod {
+		t.Errorf("Toolchains module [%s]", list[0].Module)
+	}
+
+	rtn, err := m.Switch(ctx, "1.21.0")
+	if err != nil {
+		t.Fatalf("Switch error[%v]", err)
+	}
+	if len(serv.Requests()) != 0 {
+		t.Errorf("Switch downloaded %v", serv.Requests())
+	}
+
+	b, err := os.ReadFile(filepath.Join(rtn.Path, "go.env"))
+	if err != nil {
+		t.Fatalf("ReadFile error[%v]", err)
+	}
+	if want := "GOPROXY=https://proxy.golang.org,direct\nGOTOOLCHAIN=local\n"; string(b) != want {
+		t.Errorf("go.env [%s]", b)
+	}
+
+	mf, err := m.Manifest()
+	if err != nil {
+		t.Fatalf("Manifest error[%v]", err)
+	}
+	entry := mf.SDKs["1.21.0"]
+	if entry == nil || entry.Method != golin.MethodToolchain || entry.URL != "golang.org/toolchain@"+mod {
+		t.Errorf("Manifest entry %+v", entry)
+	}
+
+	//go.envの書き込みは記録したファイルの一覧にも反映する
+	res, err := m.Verify(ctx, "1.21.0", false)
+	if err != nil {
+		t.Fatalf("Verify error[%v]", err)
+	}
+	if !res.OK() {
+		t.Errorf("Verify modified %v missing %v", res.Modified, res.Missing)
+	}
+
+	//固定したまま別のSDKに切り替えると、元のSDKのgo.envは元に戻る
+	old := filepath.Join(root, "1.20.1", "go.env")
+	err = os.WriteFile(old, []byte("GOTOOLCHAIN=path\n"), 0644)
+	if err != nil {
+		t.Fatalf("WriteFile error[%v]", err)
+	}
+	_, err = m.Switch(ctx, "1.20.1")
+	if err != nil {
+		t.Fatalf("Switch pinned error[%v]", err)
+	}
+	b, _ = os.ReadFile(filepath.Join(rtn.Path, "go.env"))
+	if want := files["go.env"]; string(b) != want {
+		t.Errorf("go.env not restored [%s]", b)
+	}
+	b, _ = os.ReadFile(old)
+	if string(b) != "GOTOOLCHAIN=local\n" {
+		t.Errorf("go.env not pinned [%s]", b)
+	}
+	mf, err = m.Manifest()
+	if err != nil {
+		t.Fatalf("Manifest error[%v]", err)
+	}
+	if len(mf.Pins) != 1 || mf.Pins["1.20.1"] != "GOTOOLCHAIN=path" {
+		t.Errorf("Manifest pins %v", mf.Pins)
+	}
+
+	//固定せずに切り替えると固定は元に戻る
+	plain := serv.NewManager(t, root)
+	_, err = plain.Switch(ctx, "1.20.1")
+	if !errors.Is(err, golin.ErrAlreadyCurrent) {
+		t.Fatalf("Switch error[%v]", err)
+	}
+	b, _ = os.ReadFile(old)
+	if string(b) != "GOTOOLCHAIN=path\n" {
+		t.Errorf("go.env not restored [%s]", b)
+	}
+	mf, err = m.Manifest()
+	if err != nil {
+		t.Fatalf("Manifest error[%v]", err)
+	}
+	if len(mf.Pins) != 0 {
+		t.Errorf("Manifest pins %v", mf.Pins)
+	}
+	res, err = m.Verify(ctx, "1.21.0", false)
+	if err != nil {
+		t.Fatalf("Verify error[%v]", err)
+	}
+	if !res.OK() {
+		t.Errorf("Verify restored modified %v missing %v", res.Modified, res.Missing)
+	}
+
+	imported, err := m.ImportToolchains(ctx, "")
+	if err != nil || len(imported) != 0 {
+		t.Errorf("ImportToolchains installed %v error[%v]", imported, err)
+	}
+	_, err = m.ImportToolchains(ctx, "1.22.0")
+	if !errors.Is(err, golin.ErrVersionNotFound) {
+		t.Errorf("ImportToolchains not found error[%v]", err)
+	}
+
+	//取り込んだSDKは書き込み可能(削除できる)
+	err = os.RemoveAll(rtn.Path)
+	if err != nil {
+		t.Errorf("RemoveAll imported SDK error[%v]", err)
+	}
+}
+
+func TestManagerImportSDK(t *testing.T) {
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("GOMODCACHE", t.TempDir())
+
+	exe := ""
+	if runtime.GOOS == "windows" {
+		exe = ".exe"
+	}
+	//golang.org/dl、システム、VERSIONのないディレクトリ
+	fakeSDK := func(dir, v string) string {
+		files := map[string]string{
+			"bin/go" + exe: golintest.StubGo(v),
+			"go.env":       "GOTOOLCHAIN=auto\n",
+		}
+		if v != "" {
+			files["VERSION"] = "go" + v + "\ntime 2023-09-06T15:00:00Z\n"
+		}
+		for name, body := range files {
+			fn := filepath.Join(dir, filepath.FromSlash(name))
+			err := os.MkdirAll(filepath.Dir(fn), 0755)
+			if err != nil {
+				t.Fatalf("MkdirAll error[%v]", err)
+			}
+			err = os.WriteFile(fn, []byte(body), 0755)
+			if err != nil {
+				t.Fatalf("WriteFile error[%v]", err)
+			}
+		}
+		return dir
+	}
+	dl := fakeSDK(filepath.Join(home, "sdk", "go1.20.8"), "1.20.8")
+	fakeSDK(filepath.Join(home, "sdk", "gotip"), "")
+	system := fakeSDK(filepath.Join(t.TempDir(), "go"), "1.21.1")
+	brew := fakeSDK(filepath.Join(t.TempDir(), "libexec"), "1.19.13")
+
+	root := createFakeRoot(t, "", "1.20.1")
+	m, err := golin.NewManager(golin.SetRoot(root), golin.SetOutput(io.Discard, io.Discard))
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+
+	ctx := context.Background()
+	list, err := m.ScanSDKs(ctx)
+	if err != nil {
+		t.Fatalf("ScanSDKs error[%v]", err)
+	}
+	found := false
+	for _, elm := range list {
+		if strings.HasPrefix(elm.Path, home) {
+			if found || elm.Version.String() != "1.20.8" || elm.Kind != "golang.org/dl" || elm.Installed {
+				t.Errorf("ScanSDKs %+v", elm)
+			}
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ScanSDKs not found %s", dl)
+	}
+
+	tests := []struct {
+		path string
+		mode golin.ImportMode
+		ver  string
+	}{
+		{system, golin.ImportCopy, "1.21.1"},
+		{dl, golin.ImportMove, "1.20.8"},
+		{brew, golin.ImportLink, "1.19.13"},
+	}
+	for _, test := range tests {
+		rtn, err := m.ImportSDK(ctx, test.path, test.mode)
+		if err != nil {
+			t.Fatalf("ImportSDK(%s) error[%v]", test.mode, err)
+		}
+		if rtn.Version.String() != test.ver || rtn.Path != filepath.Join(root, test.ver) || rtn.Mode != test.mode {
+			t.Errorf("ImportSDK(%s) %+v", test.mode, rtn)
+		}
+		_, err = os.Stat(test.path)
+		if exists := err == nil; exists == (test.mode == golin.ImportMove) {
+			t.Errorf("ImportSDK(%s) source exists [%v]", test.mode, exists)
+		}
+	}
+
+	installed, err := m.Installed(ctx)
+	if err != nil {
+		t.Fatalf("Installed error[%v]", err)
+	}
+	if len(installed) != 4 {
+		t.Errorf("Installed %d", len(installed))
+	}
+
+	mf, err := m.Manifest()
+	if err != nil {
+		t.Fatalf("Manifest error[%v]", err)
+	}
+	for _, test := range tests {
+		entry := mf.SDKs[test.ver]
+		if entry == nil || entry.Method != golin.MethodImport {
+			t.Errorf("Manifest %s %+v", test.ver, entry)
+		}
+		res, err := m.Verify(ctx, test.ver, false)
+		if err != nil || !res.OK() {
+			t.Errorf("Verify %s %+v error[%v]", test.ver, res, err)
+		}
+	}
+
+	//参照しているSDKのgo.envは変更しない
+	pinned, err := golin.NewManager(golin.SetRoot(root), golin.SetToolchainLocal(true),
+		golin.SetOutput(io.Discard, io.Discard))
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+	_, err = pinned.Switch(ctx, "1.19.13")
+	if err != nil {
+		t.Fatalf("Switch linked SDK error[%v]", err)
+	}
+	if b, err := os.ReadFile(filepath.Join(brew, "go.env")); err != nil || string(b) != "GOTOOLCHAIN=auto\n" {
+		t.Errorf("go.env of the linked SDK [%s] error[%v]", b, err)
+	}
+	_, err = pinned.Switch(ctx, "1.20.1")
+	if err != nil {
+		t.Fatalf("Switch error[%v]", err)
+	}
+
+	_, err = m.ImportSDK(ctx, system, golin.ImportCopy)
+	if !errors.Is(err, golin.ErrAlreadyInstalled) {
+		t.Errorf("ImportSDK installed error[%v]", err)
+	}
+	if golin.ExitCode(err) != golin.ExitError {
+		t.Errorf("ExitCode(%v) = %d", err, golin.ExitCode(err))
+	}
+	_, err = m.ImportSDK(ctx, filepath.Join(root, "1.21.1"), golin.ImportLink)
+	if !errors.Is(err, golin.ErrAlreadyInstalled) {
+		t.Errorf("ImportSDK in the root error[%v]", err)
+	}
+	_, err = m.ImportSDK(ctx, filepath.Join(home, "sdk", "gotip"), golin.ImportCopy)
+	if err == nil {
+		t.Errorf("ImportSDK without VERSION is not error")
+	}
+
+	//参照はリンクのみ削除する
+	_, err = m.Remove(ctx, "1.19.13")
+	if err != nil {
+		t.Fatalf("Remove error[%v]", err)
+	}
+	if _, err := os.Stat(filepath.Join(brew, "VERSION")); err != nil {
+		t.Errorf("Remove deleted the linked SDK[%v]", err)
+	}
+}
@@ -0,0 +1,302 @@
+package golin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// manifestFile is manifest file name in the root
+//
+// 「.」で始まる為、バージョンの一覧には含まれません
+const manifestFile = ".golin.json"
+
+// manifestVersion is format version of the manifest
+const manifestVersion = 1
+
+// InstallMethod is how the SDK was installed
+type InstallMethod string
+
+const (
+	MethodArchive   InstallMethod = "archive"       //アーカイブをダウンロードして展開
+	MethodDownload  InstallMethod = "golang.org/dl" //golang.org/dl/goX.x.xでダウンロード
+	MethodSource    InstallMethod = "source"        //ソースからビルド
+	MethodToolchain InstallMethod = "toolchain"     //goコマンドがダウンロードしたgolang.org/toolchainを取り込み
+	MethodImport    InstallMethod = "import"        //既存のSDKを取り込み(URLは元のディレクトリ)
+)
+
+// Manifest is installed SDK records
+//
+// ルートに存在するSDKの入手元を記録します
+// SDKsのキーはルート以下のディレクトリ名です
+type Manifest struct {
+	Format int                       `json:"format"`
+	SDKs   map[string]*ManifestEntry `json:"sdks"`
+	//golang.org/dlでルート外に作成したファイル(パスがキー)
+	Artifacts map[string]*Artifact `json:"artifacts,omitempty"`
+	//go.envにGOTOOLCHAIN=localを書き込んだSDK(書き込む前のGOTOOLCHAINの行、ない場合は空)
+	Pins map[string]string `json:"pins,omitempty"`
+}
+
+// ManifestEntry is installed SDK record
+type ManifestEntry struct {
+	Version     string        `json:"version"`
+	URL         string        `json:"url,omitempty"`    //ダウンロード元(ソースの場合はリポジトリ@コミット)
+	SHA256      string        `json:"sha256,omitempty"` //アーカイブのチェックサム
+	InstalledAt time.Time     `json:"installed_at"`
+	LastUsed    time.Time     `json:"last_used,omitempty"`
+	Method      InstallMethod `json:"method"`
+	Platform    string        `json:"platform"` //GOOS/GOARCH
+}
+
+// platform is GOOS/GOARCH
+func platform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// manifestPath is manifest file path
+func (m *Manager) manifestPath() string {
+	return filepath.Join(m.root, manifestFile)
+}
+
+//
+// Manifest is load the manifest
+//
+// マニフェストが存在しない場合は空のマニフェストを返します
+//
+func (m *Manager) Manifest() (*Manifest, error) {
+
+	if m.root == "" {
+		return nil, errNoRoot
+	}
+
+	mf := Manifest{
+		Format: manifestVersion,
+		SDKs:   make(map[string]*ManifestEntry),
+	}
+
+	b, err := os.ReadFile(m.manifestPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return &mf, nil
+	} else if err != nil {
+		return nil, classifyPermission(xerrors.Errorf("os.ReadFile(): %w", err))
+	}
+
+	err = json.Unmarshal(b, &mf)
+	if err != nil {
+		return nil, xerrors.Errorf("manifest %s: %w", m.manifestPath(), err)
+	}
+	if mf.SDKs == nil {
+		mf.SDKs = make(map[string]*ManifestEntry)
+	}
+	return &mf, nil
+}
+
+//
+// saveManifest is write the manifest
+//
+// 書き込み途中で壊れないように一時ファイルに書き込んでから入れ替えます
+//
+func (m *Manager) saveManifest(mf *Manifest) error {
+
+	mf.Format = manifestVersion
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(mf)
+	if err != nil {
+		return xerrors.Errorf("json Encode(): %w", err)
+	}
+
+	path := m.manifestPath()
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, buf.Bytes(), 0666)
+	if err != nil {
+		return classifyPermission(xerrors.Errorf("os.WriteFile(): %w", err))
+	}
+
+	err = os.Rename(tmp, path)
+	if err != nil {
+		os.Remove(tmp)
+		return classifyPermission(xerrors.Errorf("os.Rename(): %w", err))
+	}
+	m.chown(path)
+	return nil
+}
+
+//
+// updateManifest is update the manifest
+//
+// マニフェストの更新に失敗してもSDKの操作は成功している為、
+// エラーは警告として出力します
+//
+func (m *Manager) updateManifest(fn func(*Manifest)) {
+
+	mf, err := m.Manifest()
+	if err != nil {
+		m.logger.Warn("load manifest", "path", m.manifestPath(), "error", err)
+		return
+	}
+
+	fn(mf)
+
+	err = m.saveManifest(mf)
+	if err != nil {
+		m.logger.Warn("save manifest", "path", m.manifestPath(), "error", err)
+		return
+	}
+	m.logger.Debug("manifest updated", "path", m.manifestPath())
+}
+
+// recordInstall is add the installed SDK to the manifest
+func (m *Manager) recordInstall(dir string, entry *ManifestEntry) {
+	now := time.Now()
+	entry.InstalledAt = now
+	entry.LastUsed = now
+	if entry.Platform == "" {
+		entry.Platform = platform()
+	}
+	m.updateManifest(func(mf *Manifest) {
+		mf.SDKs[dir] = entry
+	})
+}
+
+// recordUse is update the last used time
+func (m *Manager) recordUse(dir string) {
+	m.updateManifest(func(mf *Manifest) {
+		if entry, ok := mf.SDKs[dir]; ok {
+			entry.LastUsed = time.Now()
+		}
+	})
+}
+
+// recordRemove is remove the SDK from the manifest
+func (m *Manager) recordRemove(dir string) {
+	m.updateManifest(func(mf *Manifest) {
+		delete(mf.SDKs, dir)
+		delete(mf.Pins, dir)
+	})
+}
+
+// InventoryStatus is state of the SDK directory
+type InventoryStatus string
+
+const (
+	StatusOK        InventoryStatus = "ok"        //マニフェストと一致
+	StatusUntracked InventoryStatus = "untracked" //マニフェストに記録がない
+	StatusMissing   InventoryStatus = "missing"   //記録があるがディレクトリがない
+	StatusCorrupted InventoryStatus = "corrupted" //goコマンドがない、VERSIONが一致しない
+)
+
+// InventoryEntry is result of Manager.Inventory
+type InventoryEntry struct {
+	Dir      string          //ルート以下のディレクトリ名
+	Path     string          //ディレクトリ
+	Status   InventoryStatus //状態
+	Reason   string          //StatusCorruptedの理由
+	Manifest *ManifestEntry  //マニフェストの記録(StatusUntrackedの場合はnil)
+}
+
+//
+// Inventory is validate the SDK directories
+//
+// ルートのディレクトリとマニフェストを突き合わせ、
+// 記録のないディレクトリ、ディレクトリのない記録、壊れたSDKを検出します
+//
+func (m *Manager) Inventory(ctx context.Context) ([]*InventoryEntry, error) {
+
+	mf, err := m.Manifest()
+	if err != nil {
+		return nil, xerrors.Errorf("Manifest(): %w", err)
+	}
+
+	installed, err := m.Installed(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("Installed(): %w", err)
+	}
+
+	exists := make(map[string]bool, len(installed))
+	list := make([]*InventoryEntry, 0, len(installed)+len(mf.SDKs))
+
+	for _, elm := range installed {
+		dir := filepath.Base(elm.Path)
+		exists[dir] = true
+
+		entry := InventoryEntry{
+			Dir:      dir,
+			Path:     elm.Path,
+			Status:   StatusOK,
+			Manifest: mf.SDKs[dir],
+		}
+
+		ver := dir
+		if entry.Manifest == nil {
+			entry.Status = StatusUntracked
+		} else {
+			ver = entry.Manifest.Version
+		}
+
+		if reason := validateSDK(elm.Path, ver); reason != "" {
+			entry.Status = StatusCorrupted
+			entry.Reason = reason
+		}
+		list = append(list, &entry)
+	}
+
+	for dir, elm := range mf.SDKs {
+		if exists[dir] {
+			continue
+		}
+		list = append(list, &InventoryEntry{
+			Dir:      dir,
+			Path:     filepath.Join(m.root, dir),
+			Status:   StatusMissing,
+			Manifest: elm,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return NewVersion(list[i].Dir).Less(NewVersion(list[j].Dir))
+	})
+	return list, nil
+}
+
+//
+// validateSDK is SDK directory check
+//
+// goコマンドが存在し、VERSIONファイルがバージョンと一致するかを確認します
+// 問題がない場合は空文字を返します
+//
+func validateSDK(path, ver string) string {
+
+	bin := filepath.Join(path, "bin", exeName("go"))
+	if info, err := os.Stat(bin); err != nil || info.IsDir() {
+		return "go command not found"
+	}
+
+	b, err := os.ReadFile(filepath.Join(path, "VERSION"))
+	if err != nil {
+		return "VERSION not found"
+	}
+
+	//1行目がバージョン(go1.21.0)、開発版は「devel +...」
+	line := strings.TrimSpace(strings.SplitN(string(b), "\n", 2)[0])
+	if ver == CompileSDK || strings.HasPrefix(line, "devel") {
+		return ""
+	}
+	if line != "go"+ver {
+		return "VERSION mismatch: " + line
+	}
+	return ""
+}
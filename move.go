@@ -0,0 +1,179 @@
+package golin
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+)
+
+// moveSuffix is temporary directory suffix of the copy
+const moveSuffix = ".golin-move"
+
+//
+// moveDir is move the directory
+//
+// os.Renameで移動し、別のファイルシステムの場合(/home と /usr/local 等)は
+// 移動先と同じ階層の一時ディレクトリにコピーしてから入れ替え、移動元を削除します
+// コピーではパーミッション、シンボリックリンク、更新日時を維持します
+//
+func (m *Manager) moveDir(src, dst string) error {
+
+	err := os.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+	if !isCrossDevice(err) {
+		return classifyPermission(xerrors.Errorf("os.Rename(): %w", err))
+	}
+
+	m.logger.Info("copy across filesystems", "from", src, "to", dst)
+
+	tmp := dst + moveSuffix
+	err = os.RemoveAll(tmp)
+	if err != nil {
+		return classifyPermission(xerrors.Errorf("os.RemoveAll(): %w", err))
+	}
+
+	err = m.copyDir(src, tmp)
+	if err != nil {
+		os.RemoveAll(tmp)
+		return xerrors.Errorf("copyDir(): %w", err)
+	}
+
+	err = os.Rename(tmp, dst)
+	if err != nil {
+		os.RemoveAll(tmp)
+		return classifyPermission(xerrors.Errorf("os.Rename(): %w", err))
+	}
+
+	//コピーは完了している為、移動元の削除の失敗は警告のみ
+	err = os.RemoveAll(src)
+	if err != nil {
+		m.logger.Warn("remove the source", "path", src, "error", err)
+	}
+	return nil
+}
+
+//
+// copyDir is streaming recursive copy
+//
+// ファイル数で進捗を表示します
+// ディレクトリのパーミッションと更新日時は中身のコピー後に設定します
+//
+func (m *Manager) copyDir(src, dst string) error {
+
+	total := 0
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		total++
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("filepath.WalkDir(): %w", err)
+	}
+
+	bar := m.startProgress(total)
+	defer bar.Finish()
+
+	//ディレクトリは子から設定する為、逆順に処理する
+	dirs := make([]string, 0)
+	infos := make(map[string]fs.FileInfo)
+
+	err = filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		defer bar.Increment()
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			dirs = append(dirs, target)
+			infos[target] = info
+			return os.MkdirAll(target, 0700)
+		case mode&fs.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case mode.IsRegular():
+			return copyFile(p, target, info)
+		}
+		//デバイス、ソケット等はSDKに含まれない
+		m.logger.Warn("skip irregular file", "path", p, "mode", info.Mode())
+		return nil
+	})
+	if err != nil {
+		return classifyPermission(xerrors.Errorf("copy %s: %w", src, err))
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		dir := dirs[i]
+		info := infos[dir]
+		err = os.Chmod(dir, info.Mode().Perm())
+		if err != nil {
+			return classifyPermission(xerrors.Errorf("os.Chmod(): %w", err))
+		}
+		err = os.Chtimes(dir, info.ModTime(), info.ModTime())
+		if err != nil {
+			return xerrors.Errorf("os.Chtimes(): %w", err)
+		}
+	}
+	return nil
+}
+
+// copyFile is copy the regular file with the mode and mtime
+func copyFile(src, dst string, info fs.FileInfo) error {
+
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, r)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	//umaskの影響を受けないように作成後に設定
+	err = os.Chmod(dst, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// isCrossDevice is rename error between filesystems
+func isCrossDevice(err error) bool {
+	var le *os.LinkError
+	if !errors.As(err, &le) {
+		return false
+	}
+	return isCrossDeviceErrno(le.Err)
+}
@@ -0,0 +1,218 @@
+package golin
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+
+	"github.com/shizuokago/golin/v2/i18n"
+	"golang.org/x/xerrors"
+)
+
+// Option is Manager option
+type Option func(*Manager) error
+
+// SetRoot is SDK root directory
+//
+// 指定した場合はGOROOTを参照せず、確認も行いません
+func SetRoot(root string) Option {
+	return func(m *Manager) error {
+		m.root = root
+		return nil
+	}
+}
+
+// SetGOROOT is GOROOT of the root
+//
+// 環境変数GOROOTの代わりに指定します(sudoで環境変数が引き継がれない場合等)
+// 上の階層をルートにし、リンク名でない場合は切り替え時に確認を行います
+func SetGOROOT(goroot string) Option {
+	return func(m *Manager) error {
+		if goroot == "" {
+			return xerrors.Errorf("GOROOT is empty")
+		}
+		m.goroot = filepath.Clean(goroot)
+		m.gorootSet = true
+		return nil
+	}
+}
+
+// SetLinkName is symbolic link name
+func SetLinkName(l string) Option {
+	return func(m *Manager) error {
+		if l == "" {
+			return xerrors.Errorf("link name is empty")
+		}
+		m.linkName = l
+		return nil
+	}
+}
+
+// SetLinkDir is directory of the symbolic link
+//
+// 指定しない場合はルートにリンクを作成します
+// ルートをユーザのディレクトリにし、リンクのみをシステムのディレクトリ(/usr/local等)に
+// 作成する場合に指定します
+func SetLinkDir(dir string) Option {
+	return func(m *Manager) error {
+		m.linkDir = dir
+		return nil
+	}
+}
+
+// SetSudo is privilege escalation command of the link step
+//
+// リンクの位置に書き込めない場合にリンクの作成のみをこのコマンドで実行します
+// 指定しない場合は「sudo」、引数なしの場合は昇格しません(ErrPermissionになります)
+func SetSudo(cmd ...string) Option {
+	return func(m *Manager) error {
+		m.sudo = cmd
+		return nil
+	}
+}
+
+// SetToolchainLocal is write GOTOOLCHAIN=local on switch
+//
+// 切り替え先のSDKのgo.env(1.21以降)にGOTOOLCHAIN=localを書き込み、
+// goコマンドがgo.modのtoolchain等で別のバージョンに切り替えないようにします
+func SetToolchainLocal(local bool) Option {
+	return func(m *Manager) error {
+		m.toolchainLocal = local
+		return nil
+	}
+}
+
+// SetAllowMissingChecksum is accept the archive without the published checksum
+//
+// 指定しない場合、.sha256が公開されていないアーカイブはErrChecksumMismatchにします
+// 公開しているチェックサムと一致しない場合は指定してもエラーになります
+func SetAllowMissingChecksum(allow bool) Option {
+	return func(m *Manager) error {
+		m.allowMissingChecksum = allow
+		return nil
+	}
+}
+
+// SetSource is release list and download location
+func SetSource(s *Source) Option {
+	return func(m *Manager) error {
+		if s == nil {
+			return xerrors.Errorf("source is nil")
+		}
+		m.source = s
+		return nil
+	}
+}
+
+// SetReleaseURL is golin release location for the self update
+//
+// GitHubのリリースAPI(releases/latest)と同じ形式のJSONを返すURLを指定します
+func SetReleaseURL(url string) Option {
+	return func(m *Manager) error {
+		if url == "" {
+			return xerrors.Errorf("release url is empty")
+		}
+		m.releaseURL = url
+		return nil
+	}
+}
+
+// SetSourceRepository is git repository of the development SDK
+//
+// CompileSDKのビルドでcloneするリポジトリとブランチを指定します
+// ミラーや手元のリポジトリを利用する場合に指定します
+func SetSourceRepository(url, branch string) Option {
+	return func(m *Manager) error {
+		if url == "" || branch == "" {
+			return xerrors.Errorf("source repository is empty")
+		}
+		m.sourceRepository = url
+		m.sourceBranch = branch
+		return nil
+	}
+}
+
+// SetVulnDB is Go vulnerability database location
+//
+// vulndbのミラーのURL、ローカルのパスを指定します(LoadVulnDBを参照)
+// 空文字の場合は脆弱性を確認しません
+func SetVulnDB(location string) Option {
+	return func(m *Manager) error {
+		m.vulnDB = location
+		m.vulns = nil
+		m.vulnLoaded = false
+		return nil
+	}
+}
+
+// SetHTTPClient is HTTP client for the list and download
+func SetHTTPClient(c *http.Client) Option {
+	return func(m *Manager) error {
+		if c == nil {
+			return xerrors.Errorf("http client is nil")
+		}
+		m.client = c
+		return nil
+	}
+}
+
+// SetLogger is logger
+func SetLogger(l *slog.Logger) Option {
+	return func(m *Manager) error {
+		if l == nil {
+			return xerrors.Errorf("logger is nil")
+		}
+		m.logger = l
+		return nil
+	}
+}
+
+// SetOutput is output writers
+//
+// 進捗等のメッセージはstdout、コマンドのエラー出力等はstderrに出力します
+func SetOutput(stdout, stderr io.Writer) Option {
+	return func(m *Manager) error {
+		if stdout == nil || stderr == nil {
+			return xerrors.Errorf("output writer is nil")
+		}
+		m.stdout = stdout
+		m.stderr = stderr
+		return nil
+	}
+}
+
+// SetPrompter is confirmation prompter
+func SetPrompter(p Prompter) Option {
+	return func(m *Manager) error {
+		if p == nil {
+			return xerrors.Errorf("prompter is nil")
+		}
+		m.prompter = p
+		return nil
+	}
+}
+
+// SetProgress is progress bar display
+//
+// 指定しない場合は出力先が端末の場合のみ進捗バーを表示します
+func SetProgress(p bool) Option {
+	return func(m *Manager) error {
+		m.progress = p
+		m.progressSet = true
+		return nil
+	}
+}
+
+// SetLanguage is message language
+//
+// 指定しない場合は環境変数LC_ALL,LC_MESSAGES,LANGから決定します
+func SetLanguage(lang i18n.Lang) Option {
+	return func(m *Manager) error {
+		if i18n.Catalog(lang) == nil {
+			return xerrors.Errorf("unknown language: %s", lang)
+		}
+		m.msg = i18n.NewPrinter(lang)
+		return nil
+	}
+}
@@ -0,0 +1,130 @@
+package golin
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// sudoUser is user who invoked sudo
+//
+// sudoで実行した場合に環境変数SUDO_USER、SUDO_UID、SUDO_GIDから決定します
+type sudoUser struct {
+	name string
+	uid  int
+	gid  int
+	home string
+}
+
+//
+// userGOROOT is GOROOT of the go env file
+//
+// sudoでは環境変数GOROOTが引き継がれない為、
+// 実行したユーザの「go env -w」の設定ファイルからGOROOTを取得します
+// os.UserConfigDirと同じく、XDG_CONFIG_HOME(macOS以外)が指定されている場合は優先します
+//
+func userGOROOT(home string) string {
+
+	conf := userDir("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	if runtime.GOOS == "darwin" {
+		conf = filepath.Join(home, "Library", "Application Support")
+	}
+
+	f, err := os.Open(filepath.Join(conf, "go", "env"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scan := bufio.NewScanner(f)
+	for scan.Scan() {
+		key, val, ok := strings.Cut(strings.TrimSpace(scan.Text()), "=")
+		if ok && key == "GOROOT" {
+			return val
+		}
+	}
+	return ""
+}
+
+//
+// userRoot is per-user SDK root of the sudo user
+//
+// DefaultRootと同じく、XDG_DATA_HOMEが指定されている場合は優先します(sudo -E等で引き継いだ場合)
+//
+func (u *sudoUser) userRoot() string {
+	return filepath.Join(userDir("XDG_DATA_HOME", filepath.Join(u.home, ".local", "share")), "golin")
+}
+
+// userDir is XDG base directory (def if not set or not absolute)
+func userDir(env, def string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	return def
+}
+
+//
+// mkdirAll is os.MkdirAll with owner restoring
+//
+// 作成した最上位のディレクトリ以下をsudoを実行したユーザの所有にします
+//
+func (m *Manager) mkdirAll(dir string) error {
+
+	top := ""
+	for p := dir; ; p = filepath.Dir(p) {
+		if _, err := os.Lstat(p); err == nil {
+			break
+		}
+		top = p
+		if filepath.Dir(p) == p {
+			break
+		}
+	}
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	if top != "" {
+		m.chown(top)
+	}
+	return nil
+}
+
+//
+// chown is restore the owner of the created files
+//
+// sudoで実行し、作成した場所(親のディレクトリ)が実行したユーザの所有の場合に
+// path以下をそのユーザの所有に戻します
+// ホームディレクトリにrootの所有のファイルが残らないようにする為です
+// 失敗した場合は警告のみ出力します
+//
+func (m *Manager) chown(path string) {
+
+	if m.owner == nil || path == "" {
+		return
+	}
+
+	info, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		return
+	}
+	if uid, ok := fileOwner(info); !ok || uid != m.owner.uid {
+		return
+	}
+
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Lchown(p, m.owner.uid, m.owner.gid)
+	})
+	if err != nil {
+		m.logger.Warn("restore owner", "path", path, "user", m.owner.name, "error", err)
+		return
+	}
+	m.logger.Debug("owner restored", "path", path, "user", m.owner.name)
+}
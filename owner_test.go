@@ -0,0 +1,125 @@
+//go:build !windows
+
+package golin_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/shizuokago/golin/v2"
+	"github.com/shizuokago/golin/v2/internal/golintest"
+)
+
+func TestManagerSudoOwner(t *testing.T) {
+
+	if os.Geteuid() != 0 {
+		t.Skip("chown requires root")
+	}
+
+	const uid, gid = 65534, 65534
+	t.Setenv("SUDO_USER", "nobody")
+	t.Setenv("SUDO_UID", fmt.Sprint(uid))
+	t.Setenv("SUDO_GID", fmt.Sprint(gid))
+
+	//実行したユーザのホームディレクトリ
+	home := t.TempDir()
+	err := os.Chown(home, uid, gid)
+	if err != nil {
+		t.Fatalf("Chown error[%v]", err)
+	}
+	//rootの所有のディレクトリ
+	system := t.TempDir()
+
+	serv := golintest.NewServer(t, "1.20.1")
+	ctx := context.Background()
+
+	root := filepath.Join(home, "golin")
+	m := serv.NewManager(t, root)
+	_, err = m.Switch(ctx, "1.20.1")
+	if err != nil {
+		t.Fatalf("Switch error[%v]", err)
+	}
+
+	for _, p := range []string{
+		root,
+		filepath.Join(root, "current"),
+		filepath.Join(root, ".golin.json"),
+		filepath.Join(root, "1.20.1"),
+		filepath.Join(root, "1.20.1", "bin", "go"),
+	} {
+		info, err := os.Lstat(p)
+		if err != nil {
+			t.Errorf("Lstat error[%v]", err)
+			continue
+		}
+		if st := info.Sys().(*syscall.Stat_t); st.Uid != uid || st.Gid != gid {
+			t.Errorf("%s owner [%d:%d]", p, st.Uid, st.Gid)
+		}
+	}
+
+	//ユーザの所有でない場所はそのまま
+	m = serv.NewManager(t, system)
+	_, err = m.Switch(ctx, "1.20.1")
+	if err != nil {
+		t.Fatalf("Switch error[%v]", err)
+	}
+	info, err := os.Stat(filepath.Join(system, "1.20.1"))
+	if err != nil {
+		t.Fatalf("Stat error[%v]", err)
+	}
+	if st := info.Sys().(*syscall.Stat_t); st.Uid != 0 {
+		t.Errorf("system owner [%d]", st.Uid)
+	}
+}
+
+func TestManagerSudoXDG(t *testing.T) {
+
+	if os.Geteuid() != 0 {
+		t.Skip("sudo user requires root")
+	}
+
+	t.Setenv("SUDO_USER", "nobody")
+	t.Setenv("SUDO_UID", "65534")
+	t.Setenv("SUDO_GID", "65534")
+	t.Setenv("GOROOT", "")
+
+	//実行したユーザのXDGのディレクトリ(sudo -E等で引き継いだ場合)
+	data := t.TempDir()
+	conf := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", data)
+	t.Setenv("XDG_CONFIG_HOME", conf)
+
+	m, err := golin.NewManager()
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+	if want := filepath.Join(data, "golin"); m.Root() != want {
+		t.Errorf("sudo root [%s] != [%s]", m.Root(), want)
+	}
+
+	//go env -wの設定ファイル(macOSはXDG_CONFIG_HOMEを参照しない)
+	if runtime.GOOS == "darwin" {
+		return
+	}
+	goroot := filepath.Join(t.TempDir(), "go")
+	err = os.MkdirAll(filepath.Join(conf, "go"), 0755)
+	if err != nil {
+		t.Fatalf("MkdirAll error[%v]", err)
+	}
+	err = os.WriteFile(filepath.Join(conf, "go", "env"), []byte("GOROOT="+goroot+"\n"), 0644)
+	if err != nil {
+		t.Fatalf("WriteFile error[%v]", err)
+	}
+	m, err = golin.NewManager()
+	if err != nil {
+		t.Fatalf("NewManager error[%v]", err)
+	}
+	if want := filepath.Dir(goroot); m.Root() != want {
+		t.Errorf("sudo GOROOT root [%s] != [%s]", m.Root(), want)
+	}
+}
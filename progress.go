@@ -0,0 +1,65 @@
+package golin
+
+import (
+	"io"
+	"os"
+
+	"github.com/cheggaaa/pb/v3"
+)
+
+// progressBar is progress bar switchable
+//
+// 進捗表示が無効な場合は何も表示しません
+type progressBar struct {
+	bar *pb.ProgressBar
+}
+
+// startProgress is start progress bar
+func (m *Manager) startProgress(total int) *progressBar {
+	if !m.progress {
+		return &progressBar{}
+	}
+	return &progressBar{
+		bar: pb.New(total).SetWriter(m.stdout).Start(),
+	}
+}
+
+func (p *progressBar) Increment() {
+	if p.bar != nil {
+		p.bar.Increment()
+	}
+}
+
+func (p *progressBar) Finish() {
+	if p.bar != nil {
+		p.bar.Finish()
+	}
+}
+
+// isTerminal is terminal check
+//
+// 出力先が端末の場合にtrueを返します
+// ファイルやパイプの場合(CIのログ等)はfalseになります
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// countReader is read size counter
+type countReader struct {
+	r    io.Reader
+	size int64
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.size += int64(n)
+	return n, err
+}
@@ -0,0 +1,44 @@
+package golin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"golang.org/x/xerrors"
+)
+
+// Prompter is user confirmation
+//
+// 処理を続けてよいかをユーザに問い合わせます
+type Prompter interface {
+	Confirm(msg string) (bool, error)
+}
+
+// NewPrompter is reader prompter
+//
+// メッセージをwに表示し、rから読み込んだ行が「Y」の場合に許可とします
+func NewPrompter(r io.Reader, w io.Writer) Prompter {
+	return &readerPrompter{
+		scanner: bufio.NewScanner(r),
+		w:       w,
+	}
+}
+
+type readerPrompter struct {
+	scanner *bufio.Scanner
+	w       io.Writer
+}
+
+func (p *readerPrompter) Confirm(msg string) (bool, error) {
+
+	fmt.Fprint(p.w, msg)
+
+	if !p.scanner.Scan() {
+		if err := p.scanner.Err(); err != nil {
+			return false, xerrors.Errorf("scan: %w", err)
+		}
+		return false, nil
+	}
+	return p.scanner.Text() == "Y", nil
+}
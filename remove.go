@@ -0,0 +1,55 @@
+package golin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+)
+
+//
+// Remove is remove installed version
+//
+// ルートに存在するバージョンのディレクトリを削除します
+// 参照で取り込んだSDK(golin import -mode link)はシンボリックリンクのみ削除します
+// リンク先のバージョンは削除できません
+//
+func (m *Manager) Remove(ctx context.Context, ver string) (*RemoveResult, error) {
+
+	if m.root == "" {
+		return nil, errNoRoot
+	}
+
+	ver = canonicalVersion(ver)
+	if ver == "" || ver == m.linkName || filepath.Base(ver) != ver {
+		return nil, xerrors.Errorf("invalid version: %q", ver)
+	}
+
+	path := filepath.Join(m.root, ver)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, classify(ErrVersionNotFound, xerrors.Errorf("version not installed: %w", err))
+	}
+	if !info.IsDir() {
+		return nil, xerrors.Errorf("not a directory: %s", path)
+	}
+
+	if cur, err := m.Current(ctx); err == nil && cur.Path == path {
+		return nil, xerrors.Errorf("version is current: %s", ver)
+	}
+
+	err = os.RemoveAll(path)
+	if err != nil {
+		return nil, classifyPermission(xerrors.Errorf("os.RemoveAll(): %w", err))
+	}
+	m.logger.Info("removed", "version", ver, "path", path)
+	m.recordRemove(ver)
+	os.Remove(m.sumsPath(ver))
+
+	rtn := RemoveResult{
+		Version: NewVersion(ver),
+		Path:    path,
+	}
+	return &rtn, nil
+}
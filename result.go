@@ -0,0 +1,51 @@
+package golin
+
+import "time"
+
+// InstallResult is result of Manager.Install
+type InstallResult struct {
+	Version *Version
+	Path    string //SDKを展開したディレクトリ
+	Link    string //作成したシンボリックリンク
+	URL     string //ダウンロードしたアーカイブ
+}
+
+// SwitchResult is result of Manager.Switch
+type SwitchResult struct {
+	Version *Version
+	Path    string   //切り替え先のSDK
+	Link    string   //作成したシンボリックリンク
+	Before  *Version //切り替え前のgoコマンドのバージョン
+	After   *Version //切り替え後のgoコマンドのバージョン
+	//切り替え後のgoコマンドのGOTOOLCHAIN(1.21以降のgoコマンドの場合のみ)
+	Toolchain string
+}
+
+// ListEntry is version of Manager.List
+type ListEntry struct {
+	Version     *Version
+	Installed   bool      //ルートに存在するか
+	Available   bool      //ダウンロード可能か
+	Current     bool      //リンク先のバージョンか
+	Path        string    //インストール済みの場合のディレクトリ
+	InstallDate time.Time //インストールした日時
+	Size        int64     //ディスク上のサイズ(SetDiskSize()で設定)
+	ReleaseDate time.Time //リリース日(取得できない場合はゼロ値)
+	Security    bool      //セキュリティリリースか(メタデータがある場合のみ)
+}
+
+// RemoveResult is result of Manager.Remove
+type RemoveResult struct {
+	Version *Version
+	Path    string //削除したディレクトリ
+}
+
+// CurrentResult is result of Manager.Current
+type CurrentResult struct {
+	Version   *Version
+	Path      string //リンク先のディレクトリ
+	Resolved  string //シンボリックリンクを解決したディレクトリ
+	Link      string //シンボリックリンク
+	GoCommand string //PATHのgoコマンド(存在しない場合は空)
+	InPath    bool   //PATHのgoコマンドがリンク先のSDKのものか
+}
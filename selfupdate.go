@@ -0,0 +1,259 @@
+package golin
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// checksumAsset is checksum file name of the golin release
+//
+// sha256sumと同じ「ハッシュ  ファイル名」の形式です
+const checksumAsset = "SHA256SUMS"
+
+// ReleaseAsset is release zip name of the platform
+//
+// 実行中のOS、アーキテクチャのgolinのリリースZIPの名称を返します
+func ReleaseAsset() string {
+	return fmt.Sprintf("golin_%s_%s.zip", runtime.GOOS, runtime.GOARCH)
+}
+
+// golinRelease is GitHub Releases API response
+type golinRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// assetURL is download URL of the asset
+func (r *golinRelease) assetURL(name string) string {
+	for _, elm := range r.Assets {
+		if elm.Name == name {
+			return elm.URL
+		}
+	}
+	return ""
+}
+
+// SelfUpdateResult is result of Manager.SelfUpdate
+type SelfUpdateResult struct {
+	Before string //更新前のgolinのバージョン
+	After  string //更新後のgolinのバージョン
+	Path   string //置き換えた実行ファイル
+	URL    string //ダウンロードしたリリースZIP
+	SHA256 string //リリースZIPのチェックサム
+}
+
+//
+// SelfUpdate is replace the golin executable with the latest release
+//
+// リリースの位置(SetReleaseURL)から最新のリリースを取得し、
+// チェックサムファイルで検証したうえで実行ファイルを置き換えます
+// currentが最新と同じか新しい場合はErrAlreadyCurrentを返します
+// currentが空(開発中のビルド)の場合は常に置き換えます
+// exeが空の場合は実行中の実行ファイルを置き換えます
+//
+func (m *Manager) SelfUpdate(ctx context.Context, current, exe string) (*SelfUpdateResult, error) {
+
+	var err error
+	if exe == "" {
+		exe, err = os.Executable()
+		if err != nil {
+			return nil, xerrors.Errorf("os.Executable(): %w", err)
+		}
+	}
+	//シンボリックリンクの場合は実体を置き換える
+	if p, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = p
+	}
+
+	rel, err := m.fetchGolinRelease(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("fetchGolinRelease(): %w", err)
+	}
+
+	rtn := SelfUpdateResult{
+		Before: current,
+		After:  strings.TrimPrefix(rel.TagName, "v"),
+		Path:   exe,
+	}
+
+	latest := NewVersion(rtn.After)
+	if cur := NewVersion(strings.TrimPrefix(current, "v")); current != "" && cur.Mean() != MeanError &&
+		latest.Mean() != MeanError && !cur.Less(latest) {
+		return &rtn, classify(ErrAlreadyCurrent, xerrors.Errorf("golin %s is the latest", current))
+	}
+
+	name := ReleaseAsset()
+	rtn.URL = rel.assetURL(name)
+	if rtn.URL == "" {
+		return &rtn, classify(ErrVersionNotFound, xerrors.Errorf("%s is not found in the release %s", name, rel.TagName))
+	}
+
+	sumURL := rel.assetURL(checksumAsset)
+	if sumURL == "" {
+		return &rtn, xerrors.Errorf("%s is not found in the release %s", checksumAsset, rel.TagName)
+	}
+
+	sums, err := m.fetchChecksums(ctx, sumURL)
+	if err != nil {
+		return &rtn, xerrors.Errorf("fetchChecksums(): %w", err)
+	}
+	sum, ok := sums[name]
+	if !ok {
+		return &rtn, xerrors.Errorf("%s is not found in %s", name, sumURL)
+	}
+
+	m.logger.Info("self update", "from", current, "to", rtn.After, "url", rtn.URL, "path", exe)
+
+	resp, err := m.get(ctx, rtn.URL, ErrVersionNotFound)
+	if err != nil {
+		return &rtn, xerrors.Errorf("http Get error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &rtn, classifyRequest(ctx, xerrors.Errorf("io.ReadAll() error: %w", err))
+	}
+
+	h := sha256.Sum256(data)
+	rtn.SHA256 = hex.EncodeToString(h[:])
+	if rtn.SHA256 != sum {
+		return &rtn, &ChecksumError{URL: rtn.URL, Expected: sum, Actual: rtn.SHA256}
+	}
+	m.logger.Debug("checksum verified", "url", rtn.URL, "sha256", rtn.SHA256)
+
+	err = replaceExecutable(data, exe)
+	if err != nil {
+		return &rtn, xerrors.Errorf("replaceExecutable(): %w", err)
+	}
+	return &rtn, nil
+}
+
+// fetchGolinRelease is latest golin release
+func (m *Manager) fetchGolinRelease(ctx context.Context) (*golinRelease, error) {
+
+	m.logger.Debug("fetch golin release", "url", m.releaseURL)
+
+	resp, err := m.get(ctx, m.releaseURL, ErrVersionNotFound)
+	if err != nil {
+		return nil, xerrors.Errorf("http Get error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var rel golinRelease
+	err = json.NewDecoder(resp.Body).Decode(&rel)
+	if err != nil {
+		return nil, classifyRequest(ctx, xerrors.Errorf("json Decode(): %w", err))
+	}
+	if rel.TagName == "" {
+		return nil, xerrors.Errorf("tag_name is empty: %s", m.releaseURL)
+	}
+	return &rel, nil
+}
+
+//
+// fetchChecksums is checksum file
+//
+// ファイル名をキーにしたチェックサムを返します
+//
+func (m *Manager) fetchChecksums(ctx context.Context, url string) (map[string]string, error) {
+
+	resp, err := m.get(ctx, url, ErrVersionNotFound)
+	if err != nil {
+		return nil, xerrors.Errorf("http Get error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	sums := make(map[string]string)
+	scan := bufio.NewScanner(resp.Body)
+	for scan.Scan() {
+		fields := strings.Fields(scan.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		//バイナリモードの「*」を除く
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	if err := scan.Err(); err != nil {
+		return nil, classifyRequest(ctx, xerrors.Errorf("read checksum: %w", err))
+	}
+	return sums, nil
+}
+
+//
+// replaceExecutable is replace the executable with golin in the zip
+//
+// 同じディレクトリの一時ファイルに書き出してから入れ替える為、
+// 途中で失敗しても元の実行ファイルは壊れません
+//
+func replaceExecutable(data []byte, exe string) error {
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return xerrors.Errorf("zip.NewReader(): %w", err)
+	}
+
+	var bin *zip.File
+	for _, f := range zr.File {
+		if path.Base(f.Name) == exeName("golin") {
+			bin = f
+			break
+		}
+	}
+	if bin == nil {
+		return xerrors.Errorf("%s is not found in the zip", exeName("golin"))
+	}
+
+	mode := os.FileMode(0755)
+	if info, err := os.Stat(exe); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	r, err := bin.Open()
+	if err != nil {
+		return xerrors.Errorf("zip Open(): %w", err)
+	}
+	defer r.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".golin-*")
+	if err != nil {
+		return classifyPermission(xerrors.Errorf("os.CreateTemp(): %w", err))
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return xerrors.Errorf("write %s: %w", tmp.Name(), err)
+	}
+
+	err = os.Chmod(tmp.Name(), mode)
+	if err != nil {
+		return classifyPermission(xerrors.Errorf("os.Chmod(): %w", err))
+	}
+
+	err = replaceFile(tmp.Name(), exe)
+	if err != nil {
+		return classifyPermission(xerrors.Errorf("replaceFile(): %w", err))
+	}
+	return nil
+}
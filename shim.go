@@ -0,0 +1,343 @@
+package golin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// ShimEnv is version of the shim (highest priority)
+	ShimEnv = "GOLIN_VERSION"
+	// PinFile is project pin file
+	//
+	// カレントディレクトリから上の階層に向かって探します
+	PinFile = ".go-version"
+
+	//ルートに置くグローバルのデフォルトのバージョン
+	defaultFile = ".golin-version"
+	//ルートに置くシムのディレクトリ
+	shimDir = ".shims"
+)
+
+// ShimTools is commands of the shim
+var ShimTools = []string{"go", "gofmt"}
+
+// ShimSource is where the shim version came from
+type ShimSource string
+
+const (
+	ShimFromEnv     ShimSource = "env"     //環境変数GOLIN_VERSION
+	ShimFromPin     ShimSource = "pin"     //プロジェクトの.go-version
+	ShimFromDefault ShimSource = "default" //ルートの.golin-version
+	ShimFromLink    ShimSource = "link"    //シンボリックリンクのリンク先
+)
+
+// Resolution is result of Manager.Resolve
+type Resolution struct {
+	Version string     //ルート以下のディレクトリ名
+	Request string     //指定されたバージョン(1.21等のマイナーバージョンの場合がある)
+	Source  ShimSource //バージョンの指定元
+	File    string     //指定元のファイル(pin、default)
+	GOROOT  string     //SDKのディレクトリ
+}
+
+// ShimDir is default shim directory
+//
+// ルートの.shimsです。PATHの先頭に追加して利用します
+func (m *Manager) ShimDir() string {
+	if m.root == "" {
+		return ""
+	}
+	return filepath.Join(m.root, shimDir)
+}
+
+//
+// InstallShims is install go and gofmt shims
+//
+// exe(golinの実行ファイル)をdir(空の場合はShimDir)にgo、gofmtの名称でコピーします
+// golinは実行ファイル名がgo、gofmtの場合にシムとして動作し、
+// 呼び出しごとにバージョンを決定して実際のコマンドを実行します
+// シンボリックリンクを必要としない為、リンクの権限がない環境でも利用できます
+//
+func (m *Manager) InstallShims(dir, exe string) ([]string, error) {
+
+	if dir == "" {
+		dir = m.ShimDir()
+	}
+	if dir == "" {
+		return nil, errNoRoot
+	}
+
+	var err error
+	if exe == "" {
+		exe, err = os.Executable()
+		if err != nil {
+			return nil, xerrors.Errorf("os.Executable(): %w", err)
+		}
+	}
+	if p, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = p
+	}
+
+	err = m.mkdirAll(dir)
+	if err != nil {
+		return nil, classifyPermission(xerrors.Errorf("os.MkdirAll(): %w", err))
+	}
+
+	shims := make([]string, 0, len(ShimTools))
+	for _, tool := range ShimTools {
+		dst := filepath.Join(dir, exeName(tool))
+		err = copyExecutable(exe, dst)
+		if err != nil {
+			return shims, classifyPermission(xerrors.Errorf("install shim %s: %w", dst, err))
+		}
+		m.chown(dst)
+		m.logger.Info("shim installed", "path", dst)
+		shims = append(shims, dst)
+	}
+	return shims, nil
+}
+
+// copyExecutable is copy the executable through a temporary file
+//
+// 実行中のシムを置き換えられるように一時ファイルに書き出してから入れ替えます
+func copyExecutable(src, dst string) error {
+
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".golin-shim-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmp.Name(), 0755)
+	if err != nil {
+		return err
+	}
+	return replaceFile(tmp.Name(), dst)
+}
+
+//
+// SetDefault is set the global default version of the shims
+//
+// ルートの.golin-versionに書き込みます
+// 空の場合は削除し、シンボリックリンクのリンク先を利用します
+//
+func (m *Manager) SetDefault(v string) error {
+
+	if m.root == "" {
+		return errNoRoot
+	}
+
+	fn := filepath.Join(m.root, defaultFile)
+	if v == "" {
+		err := os.Remove(fn)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return classifyPermission(xerrors.Errorf("os.Remove(): %w", err))
+		}
+		return nil
+	}
+
+	err := m.mkdirAll(m.root)
+	if err != nil {
+		return classifyPermission(xerrors.Errorf("os.MkdirAll(): %w", err))
+	}
+	return m.writeVersionFile(fn, v)
+}
+
+// Pin is write the project pin file in the directory
+func (m *Manager) Pin(dir, v string) (string, error) {
+	fn := filepath.Join(dir, PinFile)
+	return fn, m.writeVersionFile(fn, v)
+}
+
+func (m *Manager) writeVersionFile(fn, v string) error {
+	if _, err := Parse(v); err != nil {
+		return xerrors.Errorf("invalid version: %w", err)
+	}
+	err := os.WriteFile(fn, []byte(NewVersion(v).String()+"\n"), 0644)
+	if err != nil {
+		return classifyPermission(xerrors.Errorf("os.WriteFile(): %w", err))
+	}
+	m.chown(fn)
+	return nil
+}
+
+//
+// Resolve is version of the shim
+//
+// 環境変数GOLIN_VERSION、dirから上の階層の.go-version、
+// ルートの.golin-version、シンボリックリンクのリンク先の順に決定します
+// 1.21のようにマイナーバージョンを指定した場合は
+// インストール済みの最新のパッチリリースを利用します
+//
+func (m *Manager) Resolve(ctx context.Context, dir string) (*Resolution, error) {
+
+	if m.root == "" {
+		return nil, errNoRoot
+	}
+
+	rtn := Resolution{}
+	if v := strings.TrimSpace(os.Getenv(ShimEnv)); v != "" {
+		rtn.Request = v
+		rtn.Source = ShimFromEnv
+	} else if fn, v := findPinFile(dir); v != "" {
+		rtn.Request = v
+		rtn.Source = ShimFromPin
+		rtn.File = fn
+	} else if v := readVersionFile(filepath.Join(m.root, defaultFile)); v != "" {
+		rtn.Request = v
+		rtn.Source = ShimFromDefault
+		rtn.File = filepath.Join(m.root, defaultFile)
+	} else {
+		cur, err := m.Current(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("Current(): %w", err)
+		}
+		rtn.Request = filepath.Base(cur.Path)
+		rtn.Version = rtn.Request
+		rtn.Source = ShimFromLink
+		rtn.GOROOT = cur.Path
+		return &rtn, nil
+	}
+
+	v, err := m.matchInstalled(ctx, rtn.Request)
+	if err != nil {
+		return &rtn, err
+	}
+	rtn.Version = v
+	rtn.GOROOT = filepath.Join(m.root, v)
+	return &rtn, nil
+}
+
+// matchInstalled is installed directory of the requested version
+func (m *Manager) matchInstalled(ctx context.Context, req string) (string, error) {
+
+	want := NewVersion(req)
+	if want.String() != "" {
+		if info, err := os.Stat(filepath.Join(m.root, want.String())); err == nil && info.IsDir() {
+			return want.String(), nil
+		}
+	}
+
+	//マイナーバージョンの指定(1.21)はインストール済みの最新のパッチ
+	if want.mean == Major && want.patch < 0 {
+		list, err := m.Installed(ctx)
+		if err != nil {
+			return "", xerrors.Errorf("Installed(): %w", err)
+		}
+		var match *Version
+		for _, elm := range list {
+			v := elm.Version
+			if v.mean == Major && minorOf(v) == minorOf(want) && (match == nil || match.Less(v)) {
+				match = v
+			}
+		}
+		if match != nil {
+			return match.String(), nil
+		}
+	}
+	return "", classify(ErrVersionNotFound, xerrors.Errorf("go%s is not installed", want))
+}
+
+// findPinFile is nearest pin file from the directory
+func findPinFile(dir string) (string, string) {
+	if dir == "" {
+		return "", ""
+	}
+	for {
+		fn := filepath.Join(dir, PinFile)
+		if v := readVersionFile(fn); v != "" {
+			return fn, v
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// readVersionFile is first line of the version file
+//
+// 「#」で始まる行と空行は無視します
+func readVersionFile(fn string) string {
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return strings.TrimPrefix(line, "go")
+	}
+	return ""
+}
+
+//
+// ShimCommand is command of the tool in the resolved SDK
+//
+// GOROOTをSDKのディレクトリにし、PATHの先頭にSDKのbinを追加します
+// (シムから起動したコマンドがさらにgoを呼び出す場合も同じバージョンになります)
+//
+func (m *Manager) ShimCommand(ctx context.Context, tool string, args []string, dir string) (*exec.Cmd, *Resolution, error) {
+
+	res, err := m.Resolve(ctx, dir)
+	if err != nil {
+		return nil, res, xerrors.Errorf("Resolve(): %w", err)
+	}
+
+	bin, err := m.Which(ctx, res.Version, tool)
+	if err != nil {
+		return nil, res, xerrors.Errorf("Which(): %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Env = shimEnv(os.Environ(), res.GOROOT)
+	return cmd, res, nil
+}
+
+// shimEnv is environment variables of the tool
+func shimEnv(env []string, goroot string) []string {
+	rtn := make([]string, 0, len(env)+2)
+	path := ""
+	for _, elm := range env {
+		key, val, _ := strings.Cut(elm, "=")
+		switch strings.ToUpper(key) {
+		case "GOROOT":
+			continue
+		case "PATH":
+			path = val
+			continue
+		}
+		rtn = append(rtn, elm)
+	}
+	bin := filepath.Join(goroot, "bin")
+	if path != "" {
+		bin += string(os.PathListSeparator) + path
+	}
+	return append(rtn, "GOROOT="+goroot, "PATH="+bin)
+}
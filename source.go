@@ -0,0 +1,37 @@
+package golin
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/shizuokago/golin/v2/config"
+)
+
+// Source is Go SDK release location
+//
+// バージョンリストを取得するページと、
+// インストール時にアーカイブをダウンロードする位置を持ちます
+// Archiveを指定した場合は切り替え時もgolang.org/dlを利用せず、
+// DownloadURLのアーカイブからインストールします
+type Source struct {
+	ListURL     string //バージョンリストのページ(golang/dl)
+	DownloadURL string //アーカイブのダウンロード先
+	Archive     bool   //切り替え時もアーカイブからインストールする
+}
+
+// DefaultSource is official release location
+func DefaultSource() *Source {
+	return &Source{
+		ListURL:     config.GitHubDownloadPage,
+		DownloadURL: config.GolangDownloadPage,
+	}
+}
+
+// ArchiveURL is archive URL of the version
+//
+// 実行中のOS、アーキテクチャのアーカイブのURLを返します
+func (s *Source) ArchiveURL(v *Version) string {
+	return fmt.Sprintf("%s/go%s.%s-%s.%s",
+		strings.TrimSuffix(s.DownloadURL, "/"), v.String(), runtime.GOOS, runtime.GOARCH, getDownloadExt())
+}
@@ -0,0 +1,447 @@
+package golin
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// toolchainPath is module path of the go command toolchain switching
+	//
+	// goコマンド(1.21以降)はGOTOOLCHAIN、go.modのtoolchainで
+	// golang.org/toolchain@v0.0.1-go{version}.{GOOS}-{GOARCH}をダウンロードします
+	toolchainPath = "golang.org/toolchain"
+	//モジュールのバージョンの接頭辞
+	toolchainPrefix = "v0.0.1-go"
+
+	//SDKのgo.env(1.21以降)
+	goEnvFile = "go.env"
+	//goコマンドによるツールチェインの切り替えを行わない設定
+	toolchainLocal = "GOTOOLCHAIN=local"
+)
+
+// Toolchain is SDK downloaded by the go command
+type Toolchain struct {
+	Version   *Version
+	Path      string //モジュールキャッシュのディレクトリ
+	Module    string //golang.org/toolchain@v0.0.1-go1.21.0.linux-amd64
+	Installed bool   //ルートに同じバージョンが存在するか
+}
+
+// supportsToolchain is go command with the toolchain switching
+//
+// 1.21以降のgoコマンドはGOTOOLCHAINにより別のバージョンに切り替わります
+func supportsToolchain(v *Version) bool {
+	if v == nil || v.mean == MeanError {
+		return false
+	}
+	if v.mean == Tip {
+		return true
+	}
+	return v.major > 1 || (v.major == 1 && v.minor >= 21)
+}
+
+// toolchainVersion is version of the toolchain module directory
+//
+// 実行中のプラットフォームと異なる場合はfalseを返します
+func toolchainVersion(name string) (*Version, bool) {
+
+	ver, ok := strings.CutPrefix(name, "toolchain@"+toolchainPrefix)
+	if !ok {
+		return nil, false
+	}
+	ver, ok = strings.CutSuffix(ver, "."+runtime.GOOS+"-"+runtime.GOARCH)
+	if !ok {
+		return nil, false
+	}
+	v, err := Parse(ver)
+	if err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+//
+// Toolchains is SDKs in the module cache
+//
+// goコマンドがダウンロードしたgolang.org/toolchainのうち、
+// 実行中のプラットフォームのものを新しい順に返します
+// モジュールキャッシュが存在しない場合は空の一覧を返します
+//
+func (m *Manager) Toolchains(ctx context.Context) ([]*Toolchain, error) {
+
+	list := make([]*Toolchain, 0)
+	cache := GetGoEnv("GOMODCACHE")
+	if cache == "" {
+		return list, nil
+	}
+
+	dirs, err := filepath.Glob(filepath.Join(cache, filepath.FromSlash(toolchainPath)+"@*"))
+	if err != nil {
+		return nil, xerrors.Errorf("filepath.Glob(): %w", err)
+	}
+
+	for _, dir := range dirs {
+		v, ok := toolchainVersion(filepath.Base(dir))
+		if !ok {
+			continue
+		}
+		//展開途中のディレクトリは対象外
+		if validateSDK(dir, v.String()) != "" {
+			m.logger.Debug("skip toolchain", "path", dir)
+			continue
+		}
+
+		tc := Toolchain{
+			Version: v,
+			Path:    dir,
+			Module:  toolchainPath + "@" + strings.TrimPrefix(filepath.Base(dir), "toolchain@"),
+		}
+		if m.root != "" {
+			if _, err := os.Stat(filepath.Join(m.root, v.String())); err == nil {
+				tc.Installed = true
+			}
+		}
+		list = append(list, &tc)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[j].Version.Less(list[i].Version)
+	})
+	return list, nil
+}
+
+//
+// ImportToolchains is copy the SDKs of the module cache to the root
+//
+// verを指定した場合はそのバージョンのみ、空の場合はインストールしていないすべてを取り込みます
+// 同じSDKを二重にダウンロードしないようにする為です
+//
+func (m *Manager) ImportToolchains(ctx context.Context, ver string) ([]*Toolchain, error) {
+
+	root, err := m.getRoot(ver)
+	if err != nil {
+		return nil, xerrors.Errorf("getRoot() error: %w", err)
+	}
+
+	list, err := m.Toolchains(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("Toolchains(): %w", err)
+	}
+
+	var want *Version
+	if ver != "" {
+		want = NewVersion(ver)
+	}
+
+	imported := make([]*Toolchain, 0, len(list))
+	for _, tc := range list {
+		if want != nil && tc.Version.String() != want.String() {
+			continue
+		}
+		if tc.Installed {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return imported, classify(ErrCancelled, xerrors.Errorf("import canceled: %w", err))
+		}
+
+		err = m.importToolchain(root, tc)
+		if err != nil {
+			return imported, xerrors.Errorf("importToolchain(): %w", err)
+		}
+		tc.Installed = true
+		imported = append(imported, tc)
+	}
+
+	if want != nil && len(imported) == 0 {
+		for _, tc := range list {
+			if tc.Version.String() == want.String() {
+				return imported, nil
+			}
+		}
+		return imported, classify(ErrVersionNotFound, xerrors.Errorf("%s@%s%s is not found in the module cache", toolchainPath, toolchainPrefix, want))
+	}
+	return imported, nil
+}
+
+//
+// findToolchain is SDK of the version in the module cache
+//
+// 存在しない場合はnilを返します
+//
+func (m *Manager) findToolchain(ctx context.Context, v string) *Toolchain {
+	list, err := m.Toolchains(ctx)
+	if err != nil {
+		m.logger.Debug("toolchains", "error", err)
+		return nil
+	}
+	for _, tc := range list {
+		if tc.Version.String() == v {
+			return tc
+		}
+	}
+	return nil
+}
+
+//
+// importToolchain is copy the toolchain module to the root
+//
+// モジュールキャッシュを壊さないように常にコピーします
+//
+func (m *Manager) importToolchain(root string, tc *Toolchain) error {
+
+	v := tc.Version.String()
+	path := filepath.Join(root, v)
+
+	m.logger.Info("import toolchain", "module", tc.Module, "to", path)
+
+	err := m.copySDK(tc.Path, path)
+	if err != nil {
+		return xerrors.Errorf("copySDK(): %w", err)
+	}
+
+	m.chown(path)
+
+	m.recordFiles(v)
+	m.recordInstall(v, &ManifestEntry{
+		Version: v,
+		URL:     tc.Module,
+		Method:  MethodToolchain,
+	})
+	return nil
+}
+
+//
+// copySDK is copy the SDK directory to the root
+//
+// 同じ階層の一時ディレクトリにコピーしてから入れ替えます
+// コピー元が読み込み専用(モジュールキャッシュ等)でも削除、変更できるように
+// 所有者の書き込み権限を付けます
+//
+func (m *Manager) copySDK(src, path string) error {
+
+	tmp := path + moveSuffix
+	err := os.RemoveAll(tmp)
+	if err != nil {
+		return classifyPermission(xerrors.Errorf("os.RemoveAll(): %w", err))
+	}
+
+	err = m.copyDir(src, tmp)
+	if err == nil {
+		err = addWritable(tmp)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		addWritable(tmp)
+		os.RemoveAll(tmp)
+		return classifyPermission(xerrors.Errorf("copy %s: %w", src, err))
+	}
+	return nil
+}
+
+// addWritable is add the owner write permission
+func addWritable(dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode().Perm()&0200 != 0 {
+			return nil
+		}
+		return os.Chmod(p, info.Mode().Perm()|0200)
+	})
+}
+
+//
+// applyToolchain is pin or restore go.env of the SDKs on switch
+//
+// SetToolchainLocalの場合は切り替え先のSDKを固定し、それ以外のSDKの固定は元に戻します
+// 指定がない場合はすべてのSDKの固定を元に戻します
+//
+func (m *Manager) applyToolchain(path string) error {
+	keep := ""
+	if m.toolchainLocal {
+		err := m.pinToolchain(path)
+		if err != nil {
+			return xerrors.Errorf("pinToolchain(): %w", err)
+		}
+		keep = filepath.Base(path)
+	}
+	err := m.unpinToolchains(keep)
+	if err != nil {
+		return xerrors.Errorf("unpinToolchains(): %w", err)
+	}
+	return nil
+}
+
+//
+// pinToolchain is write GOTOOLCHAIN=local to go.env of the SDK
+//
+// リンク先のSDKがgoコマンドにより別のバージョンに置き換えられないようにします
+// go.envが存在しないバージョン(1.20以前)は切り替えを行わない為、何もしません
+// 書き込む前のGOTOOLCHAINの行はマニフェストに記録し、unpinToolchains()で元に戻します
+// golin import -mode linkで参照しているSDKはルートの外のディレクトリの為、変更しません
+//
+func (m *Manager) pinToolchain(path string) error {
+
+	if info, err := os.Lstat(path); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		m.logger.Warn("skip pinning the linked SDK", "path", path)
+		return nil
+	}
+
+	mf, err := m.Manifest()
+	if err != nil {
+		return xerrors.Errorf("Manifest(): %w", err)
+	}
+	dir := filepath.Base(path)
+	if _, ok := mf.Pins[dir]; ok {
+		//記録済みの場合は書き込みのみ(手で変更された場合)
+		_, err = m.writeToolchain(path, toolchainLocal)
+		return err
+	}
+
+	orig, err := m.writeToolchain(path, toolchainLocal)
+	if err != nil || orig == toolchainLocal {
+		return err
+	}
+	m.logger.Info("toolchain pinned", "path", path, "original", orig)
+	m.updateManifest(func(mf *Manifest) {
+		if mf.Pins == nil {
+			mf.Pins = make(map[string]string)
+		}
+		mf.Pins[dir] = orig
+	})
+	return nil
+}
+
+//
+// unpinToolchains is restore go.env written by pinToolchain
+//
+// keep以外のSDKのgo.envを書き込む前のGOTOOLCHAINの行に戻します
+// -toolchain-localを指定せずに切り替えた場合、切り替え先以外のSDKに固定が残らないようにする為です
+//
+func (m *Manager) unpinToolchains(keep string) error {
+
+	mf, err := m.Manifest()
+	if err != nil {
+		return xerrors.Errorf("Manifest(): %w", err)
+	}
+
+	restored := make([]string, 0, len(mf.Pins))
+	for dir, orig := range mf.Pins {
+		if dir == keep {
+			continue
+		}
+		path := filepath.Join(m.root, dir)
+		_, err := m.writeToolchain(path, orig)
+		if err != nil {
+			m.updateManifest(func(mf *Manifest) {
+				for _, elm := range restored {
+					delete(mf.Pins, elm)
+				}
+			})
+			return xerrors.Errorf("restore %s: %w", path, err)
+		}
+		m.logger.Info("toolchain restored", "path", path, "line", orig)
+		restored = append(restored, dir)
+	}
+
+	if len(restored) > 0 {
+		m.updateManifest(func(mf *Manifest) {
+			for _, elm := range restored {
+				delete(mf.Pins, elm)
+			}
+		})
+	}
+	return nil
+}
+
+//
+// writeToolchain is replace the GOTOOLCHAIN line of go.env
+//
+// 最初のGOTOOLCHAINの行をlineに置き換え(空の場合は削除)、以降のGOTOOLCHAINは削除します
+// 置き換える前の最初のGOTOOLCHAINの行(ない場合は空)を返します
+// go.envが存在しない場合は何もしません
+// 記録したファイルの一覧も更新し、golin verifyで変更として扱わないようにします
+//
+func (m *Manager) writeToolchain(path, line string) (string, error) {
+
+	fn := filepath.Join(path, goEnvFile)
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return line, nil
+		}
+		return "", classifyPermission(xerrors.Errorf("os.ReadFile(): %w", err))
+	}
+
+	lines := make([]string, 0)
+	orig := ""
+	found := false
+	for _, elm := range strings.Split(strings.TrimRight(string(b), "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(elm), "GOTOOLCHAIN=") {
+			if found {
+				continue
+			}
+			found = true
+			orig = elm
+			if line == "" {
+				continue
+			}
+			elm = line
+		}
+		lines = append(lines, elm)
+	}
+	if !found && line != "" {
+		lines = append(lines, line)
+	}
+
+	data := strings.Join(lines, "\n") + "\n"
+	if data == string(b) {
+		return orig, nil
+	}
+
+	info, err := os.Stat(fn)
+	if err != nil {
+		return "", xerrors.Errorf("os.Stat(): %w", err)
+	}
+	err = os.WriteFile(fn, []byte(data), info.Mode().Perm())
+	if err != nil {
+		return "", classifyPermission(xerrors.Errorf("os.WriteFile(): %w", err))
+	}
+
+	dir := filepath.Base(path)
+	sums, err := m.loadFileSums(dir)
+	if err != nil {
+		return orig, nil
+	}
+	sum, err := hashFile(fn)
+	if err != nil {
+		m.logger.Warn("hash file", "path", fn, "error", err)
+		return orig, nil
+	}
+	sums[goEnvFile] = sum
+	err = m.saveFileSums(dir, sums)
+	if err != nil {
+		m.logger.Warn("save file list", "path", m.sumsPath(dir), "error", err)
+	}
+	return orig, nil
+}
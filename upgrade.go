@@ -0,0 +1,223 @@
+package golin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+)
+
+// Update is available patch update of the minor version
+type Update struct {
+	Installed *Version //インストール済みのマイナーバージョンの最新
+	Latest    *Version //リリースされているマイナーバージョンの最新
+	Path      string   //インストール済みのディレクトリ
+	Current   bool     //リンク先のマイナーバージョンか
+	Security  bool     //セキュリティリリースを含むか(メタデータがある場合のみ)
+}
+
+// minorKey is major and minor version
+type minorKey [2]int
+
+func minorOf(v *Version) minorKey {
+	return minorKey{v.major, v.minor}
+}
+
+func (k minorKey) less(target minorKey) bool {
+	return k[0] < target[0] || (k[0] == target[0] && k[1] < target[1])
+}
+
+// isNewer is target newer than src
+func isNewer(target, src *Version) bool {
+	return src.Less(target)
+}
+
+//
+// Outdated is patch updates of the installed versions
+//
+// インストール済みのバージョンをマイナーバージョンごとにまとめ、
+// リリースされている正式リリースの最新と比較します
+//
+func (m *Manager) Outdated(ctx context.Context) ([]*Update, error) {
+
+	list, err := m.List(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("List(): %w", err)
+	}
+
+	installed := make(map[minorKey]*ListEntry)
+	current := make(map[minorKey]bool)
+	latest := make(map[minorKey]*ListEntry)
+
+	for _, elm := range list {
+		v := elm.Version
+		if v.mean == MeanError {
+			continue
+		}
+		key := minorOf(v)
+		if elm.Installed {
+			if now, ok := installed[key]; !ok || isNewer(v, now.Version) {
+				installed[key] = elm
+			}
+			if elm.Current {
+				current[key] = true
+			}
+		}
+		if elm.Available && v.mean == Major {
+			if now, ok := latest[key]; !ok || isNewer(v, now.Version) {
+				latest[key] = elm
+			}
+		}
+	}
+
+	rtn := make([]*Update, 0)
+	for _, elm := range list {
+		key := minorOf(elm.Version)
+		inst, ok := installed[key]
+		if !ok || inst != elm {
+			continue
+		}
+		l, ok := latest[key]
+		if !ok || !isNewer(l.Version, inst.Version) {
+			continue
+		}
+		rtn = append(rtn, &Update{
+			Installed: inst.Version,
+			Latest:    l.Version,
+			Path:      inst.Path,
+			Current:   current[key],
+			Security:  m.hasSecurityRelease(list, inst.Version, l.Version),
+		})
+	}
+	return rtn, nil
+}
+
+//
+// hasSecurityRelease is security fix between the versions
+//
+// from(含まない)からto(含む)までにセキュリティリリースがあるかを返します
+// リリースのメタデータにセキュリティの情報がない場合はfalseです
+//
+func (m *Manager) hasSecurityRelease(list []*ListEntry, from, to *Version) bool {
+	for _, elm := range list {
+		if !elm.Security {
+			continue
+		}
+		v := elm.Version
+		if minorOf(v) == minorOf(to) && isNewer(v, from) && !isNewer(v, to) {
+			return true
+		}
+	}
+	return false
+}
+
+// Channel is upgrade target
+type Channel string
+
+const (
+	ChannelPatch  Channel = "patch"  //リンク先のマイナーバージョンの最新の正式リリース
+	ChannelStable Channel = "stable" //最新の正式リリース
+	ChannelPre    Channel = "pre"    //beta、rcを含めた最新
+)
+
+// UpgradeResult is result of Manager.Upgrade
+type UpgradeResult struct {
+	Before *Version      //アップグレード前のリンク先のバージョン
+	After  *Version      //アップグレード後のバージョン
+	Switch *SwitchResult //切り替えの結果
+	Pruned string        //削除した以前のバージョンのディレクトリ
+}
+
+//
+// Upgrade is switch to the latest version of the channel
+//
+// チャンネルの最新のバージョンをインストールして切り替えます
+// pruneを指定した場合は切り替え前のバージョンを削除します
+// 既に最新の場合はErrAlreadyCurrentを返します
+//
+func (m *Manager) Upgrade(ctx context.Context, channel Channel, prune bool) (*UpgradeResult, error) {
+
+	cur, err := m.Current(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("Current(): %w", err)
+	}
+
+	if channel == "" {
+		channel = ChannelPatch
+	}
+
+	releases, err := m.fetchReleases(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("fetchReleases(): %w", err)
+	}
+
+	var target *Version
+	for _, r := range releases {
+		v := r.version
+		switch channel {
+		case ChannelPatch:
+			if v.mean != Major || cur.Version.mean == MeanError || minorOf(v) != minorOf(cur.Version) {
+				continue
+			}
+		case ChannelStable:
+			if v.mean != Major {
+				continue
+			}
+		case ChannelPre:
+		default:
+			return nil, xerrors.Errorf("unknown channel: %q", channel)
+		}
+		if target == nil || isNewer(v, target) {
+			target = v
+		}
+	}
+
+	if target == nil {
+		return nil, classify(ErrVersionNotFound, xerrors.Errorf("no release of the channel %s for %s", channel, cur.Version))
+	}
+
+	rtn := UpgradeResult{
+		Before: cur.Version,
+		After:  target,
+	}
+
+	if cur.Version.mean != MeanError && !isNewer(target, cur.Version) {
+		return &rtn, classify(ErrAlreadyCurrent, xerrors.Errorf("%s is the latest of the channel %s", cur.Version, channel))
+	}
+
+	m.logger.Info("upgrade", "channel", string(channel), "from", cur.Version.String(), "to", target.String())
+
+	//インストールしていない場合は検証できるアーカイブからインストール
+	if _, err := os.Stat(filepath.Join(m.root, target.String())); os.IsNotExist(err) {
+		err = checkAuthorization(m.root)
+		if err != nil {
+			return &rtn, xerrors.Errorf("authorization error: %w", err)
+		}
+		if tc := m.findToolchain(ctx, target.String()); tc != nil {
+			err = m.importToolchain(m.root, tc)
+			if err != nil {
+				return &rtn, xerrors.Errorf("importToolchain(): %w", err)
+			}
+		} else {
+			_, _, err = m.installArchive(ctx, m.root, target)
+			if err != nil {
+				return &rtn, xerrors.Errorf("installArchive(): %w", err)
+			}
+		}
+	}
+
+	rtn.Switch, err = m.Switch(ctx, target.String())
+	if err != nil {
+		return &rtn, xerrors.Errorf("Switch(): %w", err)
+	}
+
+	if prune && rtn.Switch.Path != cur.Path {
+		_, err = m.Remove(ctx, filepath.Base(cur.Path))
+		if err != nil {
+			return &rtn, xerrors.Errorf("Remove(): %w", err)
+		}
+		rtn.Pruned = cur.Path
+	}
+	return &rtn, nil
+}
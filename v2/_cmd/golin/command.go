@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/shizuokago/golin/v2"
+	"github.com/shizuokago/golin/v2/i18n"
+)
+
+//
+// command is golin sub command
+//
+// name     コマンド名
+// args     引数の説明(golin help で表示)
+// short    一覧に表示する説明
+// long     golin help {command} で表示する説明
+// flags    コマンド固有のオプション
+// success  正常終了時に「Success.」を表示するか
+// hidden   一覧、補完に表示しない
+// raw      オプションを解析せずに引数をそのまま渡す
+//
+type command struct {
+	name    string
+	args    string
+	short   i18n.Key
+	long    i18n.Key
+	flags   func(*flag.FlagSet)
+	run     func(context.Context, []string) error
+	success bool
+	hidden  bool
+	raw     bool
+}
+
+//
+// flagSet is command options
+//
+// 共通のオプションとコマンド固有のオプションを持つFlagSetを作成します
+//
+func (c *command) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("golin "+c.name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	//コマンドの前に指定された共通のオプションを引き継ぐ
+	global := make(map[string]string)
+	flag.Visit(func(f *flag.Flag) {
+		global[f.Name] = f.Value.String()
+	})
+	addGlobalFlags(fs)
+	for name, v := range global {
+		fs.Set(name, v)
+	}
+	if c.flags != nil {
+		c.flags(fs)
+	}
+	setFlagUsage(fs)
+	return fs
+}
+
+//
+// printHelp is command help
+//
+// コマンドの使い方、説明、オプションを表示します
+//
+func (c *command) printHelp(w io.Writer) {
+
+	fmt.Fprintln(w, msg.Sprintf(i18n.CommandUsage, strings.TrimSpace(c.name+" "+c.args)))
+	fmt.Fprintln(w)
+	fmt.Fprint(w, msg.Sprintf(c.long))
+
+	fs := c.flagSet()
+	fs.SetOutput(w)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, msg.Sprintf(i18n.OptionsTitle))
+	fmt.Fprintln(w)
+	fs.PrintDefaults()
+}
+
+//
+// lookupCommand is find command by name
+//
+func lookupCommand(name string) *command {
+	for _, c := range commands {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+//
+// printCommands is command list
+//
+func printCommands(w io.Writer) {
+	fmt.Fprintln(w, msg.Sprintf(i18n.CommandsTitle))
+	fmt.Fprintln(w)
+	for _, c := range commands {
+		if c.hidden {
+			continue
+		}
+		fmt.Fprintf(w, "  %-12s%s\n", c.name, msg.Sprintf(c.short))
+	}
+	fmt.Fprintln(w)
+}
+
+//
+// isVersion is version argument check
+//
+// golin {version} で指定された引数がバージョンとして解析できるかを判定します
+// 「go」の接頭辞(go1.21.0、gotip)は許可し、ライブラリ側で除きます
+// ツールチェインの接尾辞(1.22.0-x)、メジャーのみ(1)はリリースのバージョンではない為、許可しません
+//
+func isVersion(arg string) bool {
+	if arg == golin.CompileSDK {
+		return true
+	}
+	v, err := golin.Parse(arg)
+	if err != nil {
+		return false
+	}
+	if v.Mean() == golin.Tip {
+		return true
+	}
+	x := strings.TrimPrefix(arg, "go")
+	return strings.Contains(x, ".") && !strings.Contains(x, "-")
+}
+
+//
+// unknownCommand is unknown command error
+//
+// 似ているコマンドがある場合は候補として表示します
+//
+func unknownCommand(name string) error {
+
+	text := msg.Sprintf(i18n.UnknownCommand, name)
+
+	suggests := suggestCommands(name)
+	if len(suggests) > 0 {
+		text += msg.Sprintf(i18n.DidYouMean, "\t"+strings.Join(suggests, "\n\t"))
+	}
+	return newUsageError("%s", text)
+}
+
+//
+// suggestCommands is similar command names
+//
+// 編集距離が2以下、または前方一致するコマンドを返します
+//
+func suggestCommands(name string) []string {
+	rtn := make([]string, 0)
+	for _, c := range commands {
+		if c.hidden {
+			continue
+		}
+		if distance(name, c.name) <= 2 || (len(name) >= 2 && strings.HasPrefix(c.name, name)) {
+			rtn = append(rtn, c.name)
+		}
+	}
+	return rtn
+}
+
+//
+// distance is edit distance
+//
+// 隣接文字の入れ替えも1回の編集とします(lsit -> list)
+//
+func distance(a, b string) int {
+
+	s := []rune(a)
+	t := []rune(b)
+
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := 0; j <= len(t); j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+func minInt(v int, vs ...int) int {
+	for _, elm := range vs {
+		if elm < v {
+			v = elm
+		}
+	}
+	return v
+}
+
+//
+// runHelp is golin help [command]
+//
+func runHelp(ctx context.Context, args []string) error {
+
+	if len(args) == 0 {
+		printUsage(os.Stdout)
+		return nil
+	}
+
+	c := lookupCommand(args[0])
+	if c == nil {
+		return unknownCommand(args[0])
+	}
+	c.printHelp(os.Stdout)
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shizuokago/golin/v2/i18n"
+)
+
+func TestIsVersion(t *testing.T) {
+
+	tests := []struct {
+		arg  string
+		want bool
+	}{
+		{"1.21.0", true},
+		{"1.21", true},
+		{"1.22rc1", true},
+		{"1.23beta1", true},
+		{"go1.21.0", true},
+		{"go1.22rc1", true},
+		{"tip", true},
+		{"gotip", true},
+		{"compile_sdk", true},
+		//メジャーのみ、ツールチェインの接尾辞はリリースのバージョンではない
+		{"1", false},
+		{"go1", false},
+		{"1.22.0-x", false},
+		{"go1.21.0-linux-amd64", false},
+		//コマンドの打ち間違い
+		{"lsit", false},
+		{"instal", false},
+		{"", false},
+		{"go", false},
+	}
+
+	for _, test := range tests {
+		if got := isVersion(test.arg); got != test.want {
+			t.Errorf("isVersion(%q) [%v] != [%v]", test.arg, got, test.want)
+		}
+	}
+}
+
+func TestSuggestCommands(t *testing.T) {
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"lsit", []string{"list"}},
+		{"instal", []string{"install"}},
+		{"swich", []string{"switch", "which"}},
+		{"remvoe", []string{"remove"}},
+		{"verif", []string{"verify"}},
+		{"self", []string{"self-update", "help"}},
+		{"complet", []string{"completion"}},
+		//隠しコマンドは候補にしない
+		{"__complet", []string{}},
+		{"zzzzzzzz", []string{}},
+	}
+
+	for _, test := range tests {
+		got := suggestCommands(test.name)
+		if strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("suggestCommands(%q) %v != %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+
+	orig := msg
+	msg = i18n.NewPrinter(i18n.English)
+	t.Cleanup(func() { msg = orig })
+
+	tests := []struct {
+		name    string
+		suggest bool
+	}{
+		{"lsit", true},
+		{"zzzzzzzz", false},
+	}
+
+	for _, test := range tests {
+		err := unknownCommand(test.name)
+
+		var uerr *usageError
+		if !errors.As(err, &uerr) {
+			t.Errorf("unknownCommand(%q) not usage error[%v]", test.name, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.name) {
+			t.Errorf("unknownCommand(%q) name not found [%s]", test.name, err)
+		}
+		if got := strings.Contains(err.Error(), "Did you mean"); got != test.suggest {
+			t.Errorf("unknownCommand(%q) suggest [%v] [%s]", test.name, got, err)
+		}
+	}
+}
@@ -0,0 +1,773 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shizuokago/golin/v2"
+	"github.com/shizuokago/golin/v2/config"
+	"github.com/shizuokago/golin/v2/i18n"
+)
+
+// switchCommand is golin {version}
+const switchCommand = "switch"
+
+// golinのコマンド
+//
+// helpから参照する為、init()で設定します
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{name: "install", args: "{path} [version]", short: i18n.CmdInstall, long: i18n.HelpInstall,
+			run: runInstall, success: true},
+		{name: switchCommand, args: "{version}", short: i18n.CmdSwitch, long: i18n.HelpSwitch,
+			run: runSwitch, success: true},
+		{name: "list", short: i18n.CmdList, long: i18n.HelpList,
+			flags: listFlags, run: runList},
+		{name: "remove", args: "{version}", short: i18n.CmdRemove, long: i18n.HelpRemove,
+			run: runRemove, success: true},
+		{name: "current", short: i18n.CmdCurrent, long: i18n.HelpCurrent,
+			run: runCurrent},
+		{name: "which", args: "{tool} [version]", short: i18n.CmdWhich, long: i18n.HelpWhich,
+			run: runWhich},
+		{name: "inventory", short: i18n.CmdInventory, long: i18n.HelpInventory,
+			run: runInventory},
+		{name: "verify", args: "[version]", short: i18n.CmdVerify, long: i18n.HelpVerify,
+			flags: verifyFlags, run: runVerify},
+		{name: "outdated", short: i18n.CmdOutdated, long: i18n.HelpOutdated,
+			run: runOutdated},
+		{name: "upgrade", short: i18n.CmdUpgrade, long: i18n.HelpUpgrade,
+			flags: upgradeFlags, run: runUpgrade, success: true},
+		{name: "clean", short: i18n.CmdClean, long: i18n.HelpClean,
+			flags: cleanFlags, run: runClean},
+		{name: "doctor", short: i18n.CmdDoctor, long: i18n.HelpDoctor,
+			flags: doctorFlags, run: runDoctor},
+		{name: "shim", args: "{install|default|pin|which} [version]", short: i18n.CmdShim, long: i18n.HelpShim,
+			flags: shimFlags, run: runShimCommand},
+		{name: "toolchain", args: "{list|import} [version]", short: i18n.CmdToolchain, long: i18n.HelpToolchain,
+			run: runToolchain},
+		{name: "import", args: "[path...]", short: i18n.CmdImport, long: i18n.HelpImport,
+			flags: importFlags, run: runImport},
+		{name: "dev", short: i18n.CmdDev, long: i18n.HelpDev,
+			run: runDev, success: true},
+		{name: "self-update", short: i18n.CmdSelfUpdate, long: i18n.HelpSelfUpdate,
+			flags: selfUpdateFlags, run: runSelfUpdate},
+		{name: "version", short: i18n.CmdVersion, long: i18n.HelpVersion,
+			run: runVersion},
+		{name: "completion", args: "{bash|zsh|fish|powershell}", short: i18n.CmdCompletion, long: i18n.HelpCompletion,
+			run: runCompletion},
+		{name: "help", args: "[command]", short: i18n.CmdHelp, long: i18n.HelpHelp,
+			run: runHelp},
+		{name: completeCommand, run: runComplete, hidden: true, raw: true},
+	}
+}
+
+func runInstall(ctx context.Context, args []string) error {
+
+	if len(args) < 1 {
+		return newUsageError("%s", msg.Sprintf(i18n.RequiredPath))
+	}
+
+	m, err := newManager(golin.SetRoot(args[0]))
+	if err != nil {
+		return err
+	}
+
+	v := ""
+	if len(args) >= 2 {
+		v = args[1]
+	}
+	//インストールを行う
+	_, err = m.Install(ctx, v)
+	return err
+}
+
+func runSwitch(ctx context.Context, args []string) error {
+
+	if len(args) < 1 {
+		return newUsageError("%s", msg.Sprintf(i18n.RequiredVersion))
+	}
+
+	v := args[0]
+	if !isVersion(v) {
+		return newUsageError("%s", msg.Sprintf(i18n.InvalidVersion, v))
+	}
+
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+	//バージョンの変更
+	rtn, err := m.Switch(ctx, v)
+	if err != nil {
+		return err
+	}
+	warnToolchain(rtn)
+	return nil
+}
+
+// warnToolchain is warning of the toolchain switching by the go command
+//
+// 切り替え後のgoコマンドがGOTOOLCHAINで別のバージョンに切り替わる場合に警告します
+func warnToolchain(rtn *golin.SwitchResult) {
+	if rtn == nil || rtn.Toolchain == "" || rtn.Toolchain == "local" {
+		return
+	}
+	fmt.Fprintln(os.Stderr, msg.Sprintf(i18n.WarnToolchain, rtn.After, rtn.Toolchain))
+}
+
+// golin listのオプション
+var listFilter golin.ListFilter
+
+func listFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&listFilter.Installed, "installed", false, msg.Sprintf(i18n.FlagInstalled))
+	fs.BoolVar(&listFilter.Available, "available", false, msg.Sprintf(i18n.FlagAvailable))
+	fs.BoolVar(&listFilter.Stable, "stable", false, msg.Sprintf(i18n.FlagStable))
+	fs.BoolVar(&listFilter.Pre, "pre", false, msg.Sprintf(i18n.FlagPre))
+	fs.StringVar(&listFilter.Minor, "minor", "", msg.Sprintf(i18n.FlagMinor))
+	fs.BoolVar(&listFilter.LatestPerMinor, "latest-per-minor", false, msg.Sprintf(i18n.FlagLatestPerMinor))
+	fs.StringVar(&vulnDB, "vulndb", "", msg.Sprintf(i18n.FlagVulnDB))
+}
+
+func runList(ctx context.Context, args []string) error {
+
+	if listFilter.Installed && listFilter.Available {
+		return newUsageError("%s", msg.Sprintf(i18n.ConflictFlags, "-installed", "-available"))
+	}
+	if listFilter.Stable && listFilter.Pre {
+		return newUsageError("%s", msg.Sprintf(i18n.ConflictFlags, "-stable", "-pre"))
+	}
+	if listFilter.Minor != "" && !isVersion(listFilter.Minor) {
+		return newUsageError("%s", msg.Sprintf(i18n.InvalidVersion, listFilter.Minor))
+	}
+
+	m, err := newManager(vulnDBOptions()...)
+	if err != nil {
+		return err
+	}
+
+	//インストール済みのみの場合はダウンロードのリストを取得しない
+	var list []*golin.ListEntry
+	if listFilter.Installed {
+		list, err = m.Installed(ctx)
+	} else {
+		list, err = m.List(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	list, err = listFilter.Apply(list)
+	if err != nil {
+		return err
+	}
+
+	golin.SetDiskSize(list)
+	m.PrintList(os.Stdout, list)
+
+	warnCurrent(ctx, m, list)
+	return nil
+}
+
+// golin list、doctorのオプション
+var vulnDB string
+
+// vulnDBOptions is Manager options of -vulndb
+//
+// 未指定の場合は環境変数GOVULNDBを利用する為、オプションを設定しません
+func vulnDBOptions() []golin.Option {
+	if vulnDB == "" {
+		return nil
+	}
+	return []golin.Option{golin.SetVulnDB(vulnDB)}
+}
+
+// warnCurrent is warn the version of the symbolic link
+//
+// サポートが終了している場合、既知の脆弱性がある場合に標準エラーに警告を表示します
+func warnCurrent(ctx context.Context, m *golin.Manager, list []*golin.ListEntry) {
+
+	var cur *golin.ListEntry
+	for _, elm := range list {
+		if elm.Current {
+			cur = elm
+			break
+		}
+	}
+	if cur == nil {
+		return
+	}
+
+	if cur.Version.Support() == golin.Unsupported {
+		fmt.Fprintln(os.Stderr, msg.Sprintf(i18n.WarnUnsupported, cur.Version, supportedMinors(list)))
+	}
+	if vulns, ok := m.Vulnerabilities(ctx, cur.Version); ok && len(vulns) > 0 {
+		fmt.Fprintln(os.Stderr, msg.Sprintf(i18n.WarnVulns, cur.Version, len(vulns)))
+	}
+}
+
+// supportedMinors is supported minor versions in the list
+func supportedMinors(list []*golin.ListEntry) string {
+	minors := make([]string, 0, 2)
+	for _, elm := range list {
+		v := elm.Version
+		if v.Support() != golin.Supported || v.Prerelease() {
+			continue
+		}
+		minor := fmt.Sprintf("%d.%d", v.Major(), v.Minor())
+		if !contains(minors, minor) {
+			minors = append(minors, minor)
+		}
+	}
+	return strings.Join(minors, ", ")
+}
+
+func contains(list []string, s string) bool {
+	for _, elm := range list {
+		if elm == s {
+			return true
+		}
+	}
+	return false
+}
+
+func runRemove(ctx context.Context, args []string) error {
+
+	if len(args) < 1 {
+		return newUsageError("%s", msg.Sprintf(i18n.RequiredVersion))
+	}
+
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+	_, err = m.Remove(ctx, args[0])
+	return err
+}
+
+func runCurrent(ctx context.Context, args []string) error {
+
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	cur, err := m.Current(ctx)
+	if err != nil {
+		return err
+	}
+
+	resolved := cur.Resolved
+	if resolved == "" {
+		resolved = cur.Path
+	}
+	fmt.Print(msg.Sprintf(i18n.CurrentInfo, cur.Version, resolved, cur.Link))
+
+	//PATHのgoコマンドがSDKのものでない場合は設定を促す
+	switch {
+	case cur.GoCommand == "":
+		fmt.Fprintln(os.Stderr, msg.Sprintf(i18n.GoNotFound, filepath.Join(cur.Link, "bin")))
+	case cur.InPath:
+		fmt.Print(msg.Sprintf(i18n.GoCommand, cur.GoCommand))
+	default:
+		fmt.Print(msg.Sprintf(i18n.GoCommand, cur.GoCommand))
+		fmt.Fprintln(os.Stderr, msg.Sprintf(i18n.GoNotInSDK, filepath.Join(cur.Link, "bin")))
+	}
+	return nil
+}
+
+func runWhich(ctx context.Context, args []string) error {
+
+	if len(args) < 1 {
+		return newUsageError("%s", msg.Sprintf(i18n.RequiredTool))
+	}
+
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	v := ""
+	if len(args) >= 2 {
+		v = args[1]
+	}
+	path, err := m.Which(ctx, v, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(path)
+	return nil
+}
+
+func runInventory(ctx context.Context, args []string) error {
+
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	list, err := m.Inventory(ctx)
+	if err != nil {
+		return err
+	}
+
+	problems := 0
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, msg.Sprintf(i18n.InventoryHeader))
+	for _, elm := range list {
+		ver, method, installed, used := "-", "-", "-", "-"
+		if mf := elm.Manifest; mf != nil {
+			ver = mf.Version
+			method = string(mf.Method)
+			installed = formatTime(mf.InstalledAt)
+			used = formatTime(mf.LastUsed)
+		}
+		status := string(elm.Status)
+		if elm.Reason != "" {
+			status += " (" + elm.Reason + ")"
+		}
+		if elm.Status != golin.StatusOK {
+			problems++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", elm.Dir, ver, method, installed, used, status)
+	}
+	tw.Flush()
+
+	if problems > 0 {
+		return errors.New(msg.Sprintf(i18n.InventoryProblems, problems))
+	}
+	return nil
+}
+
+// formatTime is date of the list
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// golin verifyのオプション
+var repair bool
+
+func verifyFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&repair, "repair", false, msg.Sprintf(i18n.FlagRepair))
+}
+
+func runVerify(ctx context.Context, args []string) error {
+
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	//指定がない場合はリンク先のバージョン
+	v := ""
+	if len(args) >= 1 {
+		v = args[0]
+	} else {
+		cur, err := m.Current(ctx)
+		if err != nil {
+			return err
+		}
+		v = filepath.Base(cur.Path)
+	}
+
+	rtn, err := m.Verify(ctx, v, repair)
+	if rtn != nil {
+		for _, elm := range rtn.Missing {
+			fmt.Println(msg.Sprintf(i18n.VerifyMissing, elm))
+		}
+		for _, elm := range rtn.Modified {
+			fmt.Println(msg.Sprintf(i18n.VerifyModified, elm))
+		}
+		for _, elm := range rtn.Extra {
+			fmt.Println(msg.Sprintf(i18n.VerifyExtra, elm))
+		}
+		for _, elm := range rtn.Repaired {
+			fmt.Println(msg.Sprintf(i18n.RepairedFile, elm))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(msg.Sprintf(i18n.VerifyResult, v, rtn.Files, len(rtn.Missing), len(rtn.Modified), len(rtn.Extra), rtn.Source))
+	if rtn.OK() {
+		return nil
+	}
+
+	damaged := len(rtn.Missing) + len(rtn.Modified)
+	if len(rtn.Repaired) == damaged {
+		fmt.Println(msg.Sprintf(i18n.VerifyRepaired, len(rtn.Repaired)))
+		return nil
+	}
+	return errors.New(msg.Sprintf(i18n.VerifyDamaged, v, damaged))
+}
+
+func runOutdated(ctx context.Context, args []string) error {
+
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	list, err := m.Outdated(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(list) == 0 {
+		fmt.Println(msg.Sprintf(i18n.NoUpdates))
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, msg.Sprintf(i18n.OutdatedHeader))
+	for _, elm := range list {
+		mark := " "
+		if elm.Current {
+			mark = "*"
+		}
+		security := ""
+		if elm.Security {
+			security = msg.Sprintf(i18n.OutdatedSecurity)
+		}
+		fmt.Fprintf(tw, "%s %s\t%s\t%s\n", mark, elm.Installed, elm.Latest, security)
+	}
+	return tw.Flush()
+}
+
+// golin upgradeのオプション
+var (
+	channel string
+	prune   bool
+)
+
+// channels is upgrade channel names
+var channels = []string{
+	string(golin.ChannelPatch),
+	string(golin.ChannelStable),
+	string(golin.ChannelPre),
+}
+
+func upgradeFlags(fs *flag.FlagSet) {
+	fs.StringVar(&channel, "channel", string(golin.ChannelPatch), msg.Sprintf(i18n.FlagChannel))
+	fs.BoolVar(&prune, "prune", false, msg.Sprintf(i18n.FlagPrune))
+}
+
+func runUpgrade(ctx context.Context, args []string) error {
+
+	valid := false
+	for _, elm := range channels {
+		valid = valid || elm == channel
+	}
+	if !valid {
+		return newUsageError("%s", msg.Sprintf(i18n.UnknownChannel, channel))
+	}
+
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	rtn, err := m.Upgrade(ctx, golin.Channel(channel), prune)
+	if err != nil {
+		return err
+	}
+
+	warnToolchain(rtn.Switch)
+	fmt.Println(msg.Sprintf(i18n.Upgraded, rtn.Before, rtn.After))
+	if rtn.Pruned != "" {
+		fmt.Println(msg.Sprintf(i18n.Pruned, rtn.Pruned))
+	}
+	return nil
+}
+
+// golin self-updateのオプション
+var releaseURL string
+
+func selfUpdateFlags(fs *flag.FlagSet) {
+	fs.StringVar(&releaseURL, "release-url", config.GolinReleaseURL, msg.Sprintf(i18n.FlagReleaseURL))
+}
+
+func runSelfUpdate(ctx context.Context, args []string) error {
+
+	m, err := newManager(golin.SetReleaseURL(releaseURL))
+	if err != nil {
+		return err
+	}
+
+	rtn, err := m.SelfUpdate(ctx, version, "")
+	if errors.Is(err, golin.ErrAlreadyCurrent) {
+		fmt.Println(msg.Sprintf(i18n.SelfUpToDate, rtn.Before))
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	before := rtn.Before
+	if before == "" {
+		before = "devel"
+	}
+	fmt.Println(msg.Sprintf(i18n.SelfUpdated, before, rtn.After, rtn.Path))
+	return nil
+}
+
+// golin cleanのオプション
+var dryRun bool
+
+func cleanFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&dryRun, "n", false, msg.Sprintf(i18n.FlagDryRun))
+}
+
+// artifactKeys is messages of the golang.org/dl leftovers
+var artifactKeys = map[golin.ArtifactKind]i18n.Key{
+	golin.ArtifactWrapper: i18n.CleanWrapper,
+	golin.ArtifactSDK:     i18n.CleanSDK,
+	golin.ArtifactModule:  i18n.CleanModule,
+}
+
+func runClean(ctx context.Context, args []string) error {
+
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	rtn, err := m.Clean(ctx, dryRun)
+	if rtn != nil {
+		for _, elm := range rtn.Removed {
+			fmt.Println(msg.Sprintf(artifactKeys[elm.Kind], elm.Path))
+		}
+		for _, elm := range rtn.Untracked {
+			fmt.Println(msg.Sprintf(i18n.CleanUntracked, elm.Path))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(rtn.Removed) == 0:
+		fmt.Println(msg.Sprintf(i18n.NothingToClean))
+	case rtn.DryRun:
+		fmt.Println(msg.Sprintf(i18n.CleanDryRun, len(rtn.Removed)))
+	default:
+		fmt.Println(msg.Sprintf(i18n.Cleaned, len(rtn.Removed)))
+	}
+	return nil
+}
+
+// toolchainCommands is sub commands of golin toolchain
+var toolchainCommands = []string{"list", "import"}
+
+func runToolchain(ctx context.Context, args []string) error {
+
+	sub := "list"
+	if len(args) >= 1 {
+		sub, args = args[0], args[1:]
+	}
+
+	v := ""
+	if len(args) >= 1 {
+		v = args[0]
+		if !isVersion(v) {
+			return newUsageError("%s", msg.Sprintf(i18n.InvalidVersion, v))
+		}
+	}
+
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "list":
+		list, err := m.Toolchains(ctx)
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			fmt.Println(msg.Sprintf(i18n.NoToolchains))
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, elm := range list {
+			mark := " "
+			if elm.Installed {
+				mark = "*"
+			}
+			fmt.Fprintf(w, "%s %s\t%s\n", mark, elm.Version, elm.Module)
+		}
+		return w.Flush()
+	case "import":
+		list, err := m.ImportToolchains(ctx, v)
+		for _, elm := range list {
+			fmt.Println(msg.Sprintf(i18n.ToolchainImported, elm.Version, elm.Module))
+		}
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			fmt.Println(msg.Sprintf(i18n.NoToolchains))
+		}
+	default:
+		return newUsageError("%s", msg.Sprintf(i18n.RequiredToolchainCommand, strings.Join(toolchainCommands, ", ")))
+	}
+	return nil
+}
+
+// golin importのオプション
+var (
+	importScan   bool
+	importMode   string
+	importDryRun bool
+)
+
+func importFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&importScan, "scan", false, msg.Sprintf(i18n.FlagScan))
+	fs.StringVar(&importMode, "mode", string(golin.ImportCopy), msg.Sprintf(i18n.FlagImportMode))
+	fs.BoolVar(&importDryRun, "n", false, msg.Sprintf(i18n.FlagImportDryRun))
+}
+
+func runImport(ctx context.Context, args []string) error {
+
+	mode := golin.ImportMode(importMode)
+	valid := false
+	for _, elm := range golin.ImportModes {
+		valid = valid || elm == mode
+	}
+	if !valid {
+		return newUsageError("%s", msg.Sprintf(i18n.UnknownImportMode, importMode))
+	}
+	if !importScan && len(args) < 1 {
+		return newUsageError("%s", msg.Sprintf(i18n.RequiredImportPath))
+	}
+
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	paths := args
+	if importScan {
+		list, err := m.ScanSDKs(ctx)
+		if err != nil {
+			return err
+		}
+		//同じバージョンは最初に見つかったもののみ
+		versions := make(map[string]bool)
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, elm := range list {
+			mark := " "
+			if elm.Installed {
+				mark = "*"
+			} else if !versions[elm.Version.String()] {
+				versions[elm.Version.String()] = true
+				paths = append(paths, elm.Path)
+			}
+			fmt.Fprintf(tw, "%s %s\t%s\t%s\n", mark, elm.Version, elm.Kind, elm.Path)
+		}
+		tw.Flush()
+	}
+
+	if len(paths) == 0 {
+		fmt.Println(msg.Sprintf(i18n.NoSDKsFound))
+		return nil
+	}
+	if importDryRun {
+		fmt.Println(msg.Sprintf(i18n.ImportCandidates, len(paths)))
+		return nil
+	}
+
+	//ルートに存在するものは飛ばして残りを取り込む
+	for _, p := range paths {
+		rtn, err := m.ImportSDK(ctx, p, mode)
+		if errors.Is(err, golin.ErrAlreadyInstalled) {
+			fmt.Fprintln(os.Stderr, msg.Sprintf(i18n.ImportSkipped, p, err))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(msg.Sprintf(i18n.Imported, rtn.Version, rtn.Source, rtn.Mode))
+	}
+	return nil
+}
+
+func doctorFlags(fs *flag.FlagSet) {
+	fs.StringVar(&vulnDB, "vulndb", "", msg.Sprintf(i18n.FlagVulnDB))
+}
+
+func runDoctor(ctx context.Context, args []string) error {
+
+	m, err := newManager(vulnDBOptions()...)
+	if err != nil {
+		return err
+	}
+
+	rtn, err := m.Doctor(ctx)
+	if err != nil {
+		return err
+	}
+
+	v := rtn.Current.Version
+	date := "-"
+	if !v.ReleaseDate().IsZero() {
+		date = v.ReleaseDate().Format("2006-01-02")
+	}
+	fmt.Print(msg.Sprintf(i18n.DoctorVersion, v, date))
+	fmt.Print(msg.Sprintf(i18n.DoctorSupport, rtn.Support, strings.Join(rtn.SupportedMinors, ", ")))
+
+	if rtn.Latest != nil && v.Less(rtn.Latest) {
+		fmt.Print(msg.Sprintf(i18n.DoctorUpdate, rtn.Latest))
+	} else if rtn.ListError == nil {
+		fmt.Print(msg.Sprintf(i18n.DoctorUpToDate))
+	}
+
+	if rtn.VulnDB == "" {
+		fmt.Print(msg.Sprintf(i18n.DoctorNoVulnDB))
+	} else {
+		fmt.Print(msg.Sprintf(i18n.DoctorVulns, len(rtn.Vulns)))
+		for _, elm := range rtn.Vulns {
+			fixed := ""
+			if elm.Fixed != "" {
+				fixed = " (fixed: go" + elm.Fixed + ")"
+			}
+			fmt.Printf("  %s %s%s\n    %s\n", elm.ID, elm.Summary, fixed, elm.URL)
+		}
+	}
+	fmt.Print(msg.Sprintf(i18n.DoctorNotes, rtn.ReleaseNotes))
+
+	if n := rtn.Problems(); n > 0 {
+		return errors.New(msg.Sprintf(i18n.DoctorProblems, n))
+	}
+	return nil
+}
+
+func runDev(ctx context.Context, args []string) error {
+
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+	//開発バージョンのコンパイル
+	_, err = m.Switch(ctx, golin.CompileSDK)
+	return err
+}
+
+func runVersion(ctx context.Context, args []string) error {
+	//コマンドのバージョン表示
+	//バージョン表示のみで終了(Successを表示しない)
+	printVersion()
+	return nil
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shizuokago/golin/v2"
+	"github.com/shizuokago/golin/v2/i18n"
+)
+
+// completeCommand is hidden command called by the completion scripts
+//
+// golin __complete {words...} {current}
+const completeCommand = "__complete"
+
+// remoteTimeout is timeout of the remote version list for completion
+const remoteTimeout = 3 * time.Second
+
+// 補完スクリプト
+var completionScripts = map[string]string{
+	"bash": `# golin bash completion
+# source <(golin completion bash)
+_golin() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local IFS=$'\n'
+    COMPREPLY=($(golin __complete "${COMP_WORDS[@]:1:COMP_CWORD-1}" "$cur" 2>/dev/null))
+}
+complete -o default -F _golin golin
+`,
+	"zsh": `#compdef golin
+# golin zsh completion
+# golin completion zsh > "${fpath[1]}/_golin"
+_golin() {
+    local -a candidates
+    candidates=("${(@f)$(golin __complete "${(@)words[2,CURRENT-1]}" "${words[CURRENT]}" 2>/dev/null)}")
+    compadd -a candidates
+}
+compdef _golin golin
+`,
+	"fish": `# golin fish completion
+# golin completion fish > ~/.config/fish/completions/golin.fish
+function __golin_complete
+    set -l words (commandline -opc)
+    golin __complete $words[2..-1] (commandline -ct) 2>/dev/null
+end
+complete -c golin -f -a '(__golin_complete)'
+`,
+	"powershell": `# golin PowerShell completion
+# golin completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName golin -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -ne '' -and $words.Count -gt 0) {
+        $words = @($words | Select-Object -SkipLast 1)
+    }
+    golin __complete @words $wordToComplete 2>$null | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`,
+}
+
+//
+// runCompletion is golin completion {shell}
+//
+// シェルの補完スクリプトを出力します
+//
+func runCompletion(ctx context.Context, args []string) error {
+
+	if len(args) < 1 {
+		return newUsageError("%s", msg.Sprintf(i18n.RequiredShell))
+	}
+
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		return newUsageError("%s", msg.Sprintf(i18n.UnknownShell, args[0]))
+	}
+
+	fmt.Print(script)
+	return nil
+}
+
+//
+// runComplete is completion candidates
+//
+// 最後の引数を入力中の文字列として、前方一致する候補を1行ずつ出力します
+// 補完中にエラーを表示しないように、エラーは候補なしとして扱います
+//
+func runComplete(ctx context.Context, args []string) error {
+
+	current := ""
+	if len(args) > 0 {
+		current = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	for _, elm := range completeCandidates(ctx, args, current) {
+		if strings.HasPrefix(elm, current) {
+			fmt.Println(elm)
+		}
+	}
+	return nil
+}
+
+//
+// completeCandidates is candidates for the position
+//
+// コマンドの位置ではコマンドとバージョン、
+// 各コマンドの引数の位置ではバージョンやシェルの名前を返します
+//
+func completeCandidates(ctx context.Context, words []string, current string) []string {
+
+	//値を取るオプションの直後はその値
+	if len(words) > 0 && strings.TrimLeft(words[len(words)-1], "-") == "channel" {
+		return channels
+	}
+
+	words = positionalArgs(words)
+
+	if len(words) == 0 {
+		if strings.HasPrefix(current, "-") {
+			return flagNames(nil)
+		}
+		return append(commandNames(), versions(ctx, true)...)
+	}
+
+	cmd := lookupCommand(words[0])
+	if cmd == nil {
+		return nil
+	}
+	if strings.HasPrefix(current, "-") {
+		return flagNames(cmd)
+	}
+
+	pos := len(words) - 1
+	switch cmd.name {
+	case switchCommand:
+		if pos == 0 {
+			return versions(ctx, true)
+		}
+	case "remove", "verify":
+		if pos == 0 {
+			return versions(ctx, false)
+		}
+	case "install":
+		//0番目はパスなのでシェルの補完に任せる
+		if pos == 1 {
+			return versions(ctx, true)
+		}
+	case "which":
+		if pos == 0 {
+			return []string{"go", "gofmt", "vet", "cover", "compile", "link", "asm"}
+		} else if pos == 1 {
+			return versions(ctx, false)
+		}
+	case "completion":
+		if pos == 0 {
+			return shellNames()
+		}
+	case "help":
+		if pos == 0 {
+			return commandNames()
+		}
+	}
+	return nil
+}
+
+//
+// positionalArgs is arguments without options
+//
+// 値を取るオプションは次の引数も除きます
+//
+func positionalArgs(words []string) []string {
+
+	fs := (&command{}).flagSet()
+
+	rtn := make([]string, 0, len(words))
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		if !strings.HasPrefix(w, "-") || w == "-" {
+			rtn = append(rtn, w)
+			continue
+		}
+		name := strings.TrimLeft(w, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		f := fs.Lookup(name)
+		if f == nil {
+			continue
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			continue
+		}
+		i++
+	}
+	return rtn
+}
+
+// commandNames is visible command names
+func commandNames() []string {
+	rtn := make([]string, 0, len(commands))
+	for _, c := range commands {
+		if !c.hidden {
+			rtn = append(rtn, c.name)
+		}
+	}
+	return rtn
+}
+
+// shellNames is supported shells of completion
+func shellNames() []string {
+	rtn := make([]string, 0, len(completionScripts))
+	for name := range completionScripts {
+		rtn = append(rtn, name)
+	}
+	sort.Strings(rtn)
+	return rtn
+}
+
+// flagNames is option names of the command
+func flagNames(cmd *command) []string {
+	if cmd == nil {
+		cmd = &command{}
+	}
+	rtn := make([]string, 0)
+	cmd.flagSet().VisitAll(func(f *flag.Flag) {
+		rtn = append(rtn, "-"+f.Name)
+	})
+	return rtn
+}
+
+//
+// versions is version candidates
+//
+// インストール済みのバージョンと、remoteの場合はダウンロード可能なバージョンを返します
+// ダウンロード可能なバージョンは取得に時間がかかる場合は諦めます
+//
+func versions(ctx context.Context, remote bool) []string {
+
+	m, err := golin.NewManager(
+		golin.SetLinkName(link),
+		golin.SetOutput(io.Discard, io.Discard),
+		golin.SetProgress(false))
+	if err != nil {
+		return nil
+	}
+
+	rtn := make([]string, 0)
+	exists := make(map[string]bool)
+	add := func(list []*golin.ListEntry) {
+		for _, elm := range list {
+			v := elm.Version.String()
+			if !exists[v] {
+				exists[v] = true
+				rtn = append(rtn, v)
+			}
+		}
+	}
+
+	if installed, err := m.Installed(ctx); err == nil {
+		add(installed)
+	}
+
+	if remote {
+		ctx, cancel := context.WithTimeout(ctx, remoteTimeout)
+		defer cancel()
+		if list, err := m.List(ctx); err == nil {
+			add(list)
+		}
+	}
+	return rtn
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+
+	"github.com/shizuokago/golin/v2/i18n"
+)
+
+// ログの出力形式
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+//
+// newLogger is create command logger
+//
+// verboseの場合はdebug、quietの場合はwarn以上を出力します
+// textの場合は端末で読みやすいように時刻を省略します
+//
+func newLogger(w io.Writer, verbose, quiet bool, format string) (*slog.Logger, error) {
+
+	if verbose && quiet {
+		return nil, errors.New(msg.Sprintf(i18n.VerboseQuiet))
+	}
+
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	} else if quiet {
+		level = slog.LevelWarn
+	}
+
+	switch format {
+	case LogFormatText:
+		opts := slog.HandlerOptions{
+			Level: level,
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if len(groups) == 0 && a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}
+		return slog.New(slog.NewTextHandler(w, &opts)), nil
+	case LogFormatJSON:
+		opts := slog.HandlerOptions{Level: level}
+		return slog.New(slog.NewJSONHandler(w, &opts)), nil
+	}
+
+	return nil, errors.New(msg.Sprintf(i18n.UnknownLogFormat, format))
+}
@@ -2,12 +2,18 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/shizuokago/golin/v2"
 	"github.com/shizuokago/golin/v2/config"
+	"github.com/shizuokago/golin/v2/i18n"
 )
 
 var (
@@ -18,182 +24,280 @@ var (
 )
 
 var (
-	link string
+	link    string
+	linkDir string
+	goroot  string
+	//切り替え時にgo.envにGOTOOLCHAIN=localを書き込む
+	toolchainLocal bool
+	//チェックサムが公開されていないアーカイブを許可する
+	allowMissingChecksum bool
+	verbose              bool
+	quiet                bool
+	logFormat            string
+	lang                 string
 )
 
+//コマンドのメッセージ
+var msg = i18n.NewPrinter(i18n.Detect(""))
+
 // Initialize golin command
 //
 // オプションに-dでリンク名を変更できるようにし、Usageを設定する
+// ログの出力は-verbose,-quiet,-log-format、言語は-langで変更できます
+// オプションの説明はsetLanguage()で設定します
 func init() {
-	flag.StringVar(&link, "d", config.DefaultLinkName, "symbolic link name")
+	addGlobalFlags(flag.CommandLine)
 	flag.Usage = Usage
 }
 
-type Cmd string
-
 //
-// golinコマンド
+// addGlobalFlags is common options
 //
-// version  バージョン表示
-// install  Goのインストール
-// list     ダウンロードできるバージョンのリストを表示
-// dev      最新の開発バージョンを取得
-// compress コマンド等の圧縮(リリース用)
+// 各コマンドのオプションとしても指定できるように共通のオプションを設定します
 //
-const (
-	Version         Cmd = "version"
-	Install         Cmd = "install"
-	DownloadList    Cmd = "list"
-	Development     Cmd = "dev"
-	ReleaseCompress Cmd = "compress"
-	//バージョン指定を行っている場合の文字列
-	ChangeVersion Cmd = ""
-)
+func addGlobalFlags(fs *flag.FlagSet) {
+	fs.StringVar(&link, "d", config.DefaultLinkName, "")
+	fs.StringVar(&linkDir, "link-dir", "", "")
+	fs.StringVar(&goroot, "goroot", "", "")
+	fs.BoolVar(&toolchainLocal, "toolchain-local", false, "")
+	fs.BoolVar(&allowMissingChecksum, "allow-missing-checksum", false, "")
+	fs.BoolVar(&verbose, "verbose", false, "")
+	fs.BoolVar(&quiet, "quiet", false, "")
+	fs.StringVar(&logFormat, "log-format", LogFormatText, "")
+	fs.StringVar(&lang, "lang", "", "")
+}
 
 //
 // This golin command main
 //
 func main() {
 
+	//go、gofmtの名称で実行された場合はシムとして動作する
+	if tool := shimTool(os.Args[0]); tool != "" {
+		os.Exit(runShim(tool, os.Args[1:]))
+	}
+
 	err := run()
 
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "golin Error: %+v\n", err)
-		os.Exit(1)
+		fmt.Fprint(os.Stderr, msg.Sprintf(i18n.Error, err))
+		os.Exit(exitCode(err))
 	}
 
-	os.Exit(0)
+	os.Exit(golin.ExitOK)
+}
+
+//
+// usageError is command argument error
+//
+// 引数の誤りは終了コード2(golin.ExitUsage)で終了します
+//
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func newUsageError(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
 }
 
+//
+// exitCode is process exit code
+//
+// エラーの種類ごとの終了コードはgolin.ExitCode()を参照してください
+//
+func exitCode(err error) int {
+	var uerr *usageError
+	if errors.As(err, &uerr) {
+		return golin.ExitUsage
+	}
+	return golin.ExitCode(err)
+}
+
+//
+// run is command dispatch
+//
+// 最初の引数でコマンドを決定し、コマンドのオプションを解析して実行します
+// コマンドでない場合はバージョンとして解析できる場合のみswitchを行います
+//
 func run() error {
 
 	flag.Parse()
+
+	err := setLanguage()
+	if err != nil {
+		return err
+	}
+
 	args := flag.Args()
 	if len(args) < 1 {
-		return fmt.Errorf("golin arguments required command(install,list,dev,version) or version(e.g. 1.15.6,1.16beta1).")
+		return newUsageError("%s", msg.Sprintf(i18n.RequiredCommand))
 	}
 
-	cmd := Cmd(args[0])
-	//cmd = ChangeVersion
+	name := args[0]
+	cmd := lookupCommand(name)
+	if cmd == nil {
+		if !isVersion(name) {
+			return unknownCommand(name)
+		}
+		//golin {version}
+		cmd = lookupCommand(switchCommand)
+	} else {
+		args = args[1:]
+	}
 
-	opts := make([]config.Option, 1)
-	opts[0] = config.SetLinkName(link)
+	//Ctrl-Cや停止要求でダウンロード、子プロセスをキャンセルする(引数をそのまま渡すコマンドも含む)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	err := config.Set(opts...)
-	if err != nil {
-		return fmt.Errorf("config.Set() error: %w", err)
+	if cmd.raw {
+		return cmd.run(ctx, args)
 	}
 
-	switch cmd {
-	case Version:
-		//コマンドのバージョン表示
-		err = printVersion()
-		//バージョン表示のみで終了(Successを表示しない)
+	fs := cmd.flagSet()
+	err = fs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		setLanguage()
+		cmd.printHelp(os.Stdout)
 		return nil
-	case DownloadList:
-		//ダウンロードのリスト表示
-		err = golin.PrintGoVersionList()
-	case Development:
-		//開発バージョンのコンパイル
-		err = golin.CompileLatestSDK()
-	case Install:
-		if len(args) < 2 {
-			return fmt.Errorf("golin install arguments required path")
-		}
-		path := args[1]
-		v := ""
-		if len(args) >= 3 {
-			v = args[2]
-		}
-		//インストールを行う
-		err = golin.Install(path, v)
-	case ReleaseCompress:
-		if len(args) < 3 {
-			return fmt.Errorf("golin compress arguments required filename and command name.")
-		}
-		dst := args[1]
-		src := args[2]
-		//リリース用のZip作成
-		err = golin.CompressReleaseZip(dst, src)
-	default:
-		if len(args) < 1 {
-			return fmt.Errorf("golin arguments required version(e.g. 1.15.6, 1.16beta1).")
-		}
-		v := args[0]
-		//バージョンの変更
-		err = golin.Create(v)
+	} else if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+
+	//コマンドのオプションで-langが指定された場合
+	err = setLanguage()
+	if err != nil {
+		return err
 	}
 
+	err = cmd.run(ctx, fs.Args())
 	if err != nil {
+		var uerr *usageError
+		if errors.As(err, &uerr) {
+			return err
+		}
 		return fmt.Errorf("run error: %w", err)
 	}
 
-	fmt.Println("Success.")
+	if cmd.success && !quiet {
+		fmt.Println(msg.Sprintf(i18n.Success))
+	}
 	return nil
 }
 
-func Usage() {
-	help := `Usage of golin:
-
-  It is possible to switch by setting GOROOT to a symbolic link.
-  A list of downloads is available at the link below.
-
-      https://github.com/golang/dl
-
-  まだGoが存在しない場合、
-
-      golin install {path} 
-
-  これにより最新のGoがインストールされます。
-  {path}はGOROOTの元になる位置を指定します。
-
-     e.g) golin install /usr/local/go
-
-  これを行うことで{path}/{version}にGoが設定され、
-  そのGoに対して{path}/current にシンボリックリンクを作成します。
-  install時は-vを指定することでバージョンを最新以外で選択できます。
-
-  あなたはその後、{path}/currentに対して、GOROOTの環境変数を設定する必要があります。
-
-  Goのバージョンの切り替えは
-  Please remove the "go" for the specification of the version.
-    (ex: go1.12.1 -> 1.12.1
-
-      golin 1.12.1
-
-  現在インストール可能なGoのバージョンと、インストールされているバージョンは
+//
+// newManager is create golin.Manager
+//
+// 共通のオプション(リンク名、ログ、言語、-quiet)を設定したManagerを作成します
+//
+func newManager(opts ...golin.Option) (*golin.Manager, error) {
 
-      golin list
-    
-  を実行することで一覧で表示されます。
+	logger, err := newLogger(os.Stderr, verbose, quiet, logFormat)
+	if err != nil {
+		return nil, &usageError{msg: err.Error()}
+	}
 
-  現在開発中の最新バージョン(gotip)を手に入れる場合
+	base := []golin.Option{
+		golin.SetLinkName(link),
+		golin.SetLogger(logger),
+		golin.SetLanguage(msg.Lang()),
+	}
+	if linkDir != "" {
+		base = append(base, golin.SetLinkDir(linkDir))
+	}
+	if goroot != "" {
+		base = append(base, golin.SetGOROOT(goroot))
+	}
+	if toolchainLocal {
+		base = append(base, golin.SetToolchainLocal(true))
+	}
+	if allowMissingChecksum {
+		base = append(base, golin.SetAllowMissingChecksum(true))
+	}
+	if quiet {
+		base = append(base,
+			golin.SetOutput(io.Discard, os.Stderr),
+			golin.SetProgress(false),
+			golin.SetPrompter(golin.NewPrompter(os.Stdin, os.Stdout)))
+	}
 
-      golin dev
+	m, err := golin.NewManager(append(base, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("golin.NewManager() error: %w", err)
+	}
+	return m, nil
+}
 
-  を行うとビルドして更新されます（少し時間がかかります。
-  devはビルドしたバージョンの有無ではなく、常に最新のビルドを行いますが
+//
+// Usage is command usage
+//
+// 選択されている言語で使い方、コマンド、オプション、終了コードを表示します
+//
+func Usage() {
+	printUsage(os.Stderr)
+}
 
-     golin tip
+func printUsage(w io.Writer) {
+	setLanguage()
+	fmt.Fprint(w, msg.Sprintf(i18n.Usage))
+	printCommands(w)
+	fmt.Fprintln(w, msg.Sprintf(i18n.OptionsTitle))
+	fmt.Fprintln(w)
+	flag.CommandLine.SetOutput(w)
+	flag.PrintDefaults()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, msg.Sprintf(i18n.HelpHint))
+	fmt.Fprint(w, msg.Sprintf(i18n.ExitStatus))
+}
 
-  はdevでビルドしたバージョンが存在する場合、切り替えるのみで終了します
+//
+// setLanguage is message language of the command
+//
+// -langの指定、環境変数から言語を決定し、オプションの説明を設定します
+//
+func setLanguage() error {
 
-  また-d を指定することでcurrentを変更することができます
+	if lang != "" {
+		if _, ok := i18n.Parse(lang); !ok {
+			return newUsageError("%s", msg.Sprintf(i18n.UnknownLang, lang))
+		}
+	}
 
-     e.g.) golin -d root 1.16
+	msg = i18n.NewPrinter(i18n.Detect(lang))
+	setFlagUsage(flag.CommandLine)
+	return nil
+}
 
-  これにより切り替え先のシンボリックリンクが{path}/rootになりますので、
-  そこをGOROOTに指定してください。
-`
-	fmt.Fprintf(os.Stderr, help)
-	flag.PrintDefaults()
+//
+// setFlagUsage is localize the option description
+//
+func setFlagUsage(fs *flag.FlagSet) {
+	flags := map[string]i18n.Key{
+		"d":                      i18n.FlagLink,
+		"link-dir":               i18n.FlagLinkDir,
+		"goroot":                 i18n.FlagGOROOT,
+		"toolchain-local":        i18n.FlagToolchainLocal,
+		"allow-missing-checksum": i18n.FlagAllowMissingChecksum,
+		"verbose":                i18n.FlagVerbose,
+		"quiet":                  i18n.FlagQuiet,
+		"log-format":             i18n.FlagLogFormat,
+		"lang":                   i18n.FlagLang,
+	}
+	for name, key := range flags {
+		if f := fs.Lookup(name); f != nil {
+			f.Usage = msg.Sprintf(key)
+		}
+	}
 }
 
 func printVersion() error {
 	if version == "" || revision == "" || date == "" || build == "" {
-		fmt.Println("golin development version")
-		return fmt.Errorf("version is empty.")
+		fmt.Println(msg.Sprintf(i18n.DevelopmentVersion))
+		return errors.New(msg.Sprintf(i18n.EmptyVersion))
 	}
-	fmt.Printf("golin version %s %s\nBuild Information:%s (%s)\n", version, build, date, build)
+	fmt.Print(msg.Sprintf(i18n.CommandVersion, version, build, date, build))
 	return nil
 }
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shizuokago/golin/v2"
+	"github.com/shizuokago/golin/v2/i18n"
+)
+
+//
+// shimTool is tool name when golin is run as a shim
+//
+// 実行ファイル名がgo、gofmt(golin shim installで作成)の場合にツール名を返します
+//
+func shimTool(arg0 string) string {
+	name := strings.TrimSuffix(filepath.Base(arg0), ".exe")
+	for _, tool := range golin.ShimTools {
+		if name == tool {
+			return tool
+		}
+	}
+	return ""
+}
+
+//
+// runShim is run the tool of the resolved version
+//
+// 引数はすべてツールに渡し、golinのオプションとしては解析しません
+// 終了コードはツールの終了コードです
+//
+func runShim(tool string, args []string) int {
+
+	m, err := newManager()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "golin shim: %v\n", err)
+		return exitCode(err)
+	}
+
+	dir, _ := os.Getwd()
+	cmd, res, err := m.ShimCommand(context.Background(), tool, args, dir)
+	if err != nil {
+		if errors.Is(err, golin.ErrVersionNotFound) && res != nil {
+			fmt.Fprintln(os.Stderr, msg.Sprintf(i18n.ShimNotInstalled, res.Request, shimSource(res)))
+		} else {
+			fmt.Fprintf(os.Stderr, "golin shim: %v\n", err)
+		}
+		return exitCode(err)
+	}
+	return execTool(cmd)
+}
+
+// shimSource is where the version came from
+func shimSource(res *golin.Resolution) string {
+	switch res.Source {
+	case golin.ShimFromEnv:
+		return golin.ShimEnv
+	case golin.ShimFromLink:
+		return string(res.Source)
+	}
+	return res.File
+}
+
+// golin shimのオプション
+var shimDir string
+
+func shimFlags(fs *flag.FlagSet) {
+	fs.StringVar(&shimDir, "dir", "", msg.Sprintf(i18n.FlagShimDir))
+}
+
+// shimCommands is sub commands of golin shim
+var shimCommands = []string{"install", "default", "pin", "which"}
+
+func runShimCommand(ctx context.Context, args []string) error {
+
+	if len(args) < 1 {
+		return newUsageError("%s", msg.Sprintf(i18n.RequiredShimCommand, strings.Join(shimCommands, ", ")))
+	}
+
+	m, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	sub, args := args[0], args[1:]
+	switch sub {
+	case "install":
+		shims, err := m.InstallShims(shimDir, "")
+		if err != nil {
+			return err
+		}
+		for _, elm := range shims {
+			fmt.Println(elm)
+		}
+		fmt.Print(msg.Sprintf(i18n.ShimInstalled, filepath.Dir(shims[0])))
+	case "default":
+		v := ""
+		if len(args) >= 1 {
+			v = args[0]
+			if !isVersion(v) {
+				return newUsageError("%s", msg.Sprintf(i18n.InvalidVersion, v))
+			}
+		}
+		return m.SetDefault(v)
+	case "pin":
+		if len(args) < 1 {
+			return newUsageError("%s", msg.Sprintf(i18n.RequiredVersion))
+		}
+		if !isVersion(args[0]) {
+			return newUsageError("%s", msg.Sprintf(i18n.InvalidVersion, args[0]))
+		}
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		fn, err := m.Pin(dir, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(fn)
+	case "which":
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		res, err := m.Resolve(ctx, dir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\t%s (%s)\n", res.GOROOT, res.Version, shimSource(res))
+	default:
+		return newUsageError("%s", msg.Sprintf(i18n.RequiredShimCommand, strings.Join(shimCommands, ", ")))
+	}
+	return nil
+}
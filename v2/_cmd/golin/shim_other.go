@@ -0,0 +1,21 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+//
+// execTool is replace the process with the tool
+//
+// execで置き換える為、シグナル、終了コードはツールのものになります
+//
+func execTool(cmd *exec.Cmd) int {
+	err := syscall.Exec(cmd.Path, cmd.Args, cmd.Env)
+	fmt.Fprintf(os.Stderr, "golin shim: exec %s: %v\n", cmd.Path, err)
+	return 1
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+)
+
+//
+// execTool is run the tool and return the exit code
+//
+// Windowsにはexecがない為、子プロセスとして実行して終了コードを返します
+//
+func execTool(cmd *exec.Cmd) int {
+	//Ctrl-Cはツールが処理する為、golinでは終了しない
+	signal.Ignore(os.Interrupt)
+
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	var eerr *exec.ExitError
+	if errors.As(err, &eerr) {
+		return eerr.ExitCode()
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "golin shim: %s: %v\n", cmd.Path, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,517 @@
+package golin
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// sumsDir is directory of the file lists in the root
+//
+// インストール時のファイルの一覧とSHA256を{dir}.sha256に記録します
+const sumsDir = ".golin_sums"
+
+// fileSums is SHA256 of the files
+//
+// キーはSDKのディレクトリからの相対パス(スラッシュ区切り)です
+type fileSums map[string]string
+
+// VerifyResult is result of Manager.Verify
+type VerifyResult struct {
+	Version  *Version
+	Path     string   //検証したディレクトリ
+	Source   string   //比較元(記録したファイルの一覧、またはアーカイブのURL)
+	Files    int      //比較元のファイル数
+	Missing  []string //存在しないファイル
+	Modified []string //内容が異なるファイル
+	Extra    []string //比較元に存在しないファイル
+	Repaired []string //修復したファイル
+}
+
+// OK is no missing and modified files
+//
+// 追加されたファイルは問題としません
+func (r *VerifyResult) OK() bool {
+	return len(r.Missing) == 0 && len(r.Modified) == 0
+}
+
+// sumsPath is file list path of the SDK directory
+func (m *Manager) sumsPath(dir string) string {
+	return filepath.Join(m.root, sumsDir, dir+".sha256")
+}
+
+//
+// recordFiles is record the file list of the installed SDK
+//
+// インストール直後のファイルのSHA256を記録します
+// 記録に失敗してもインストールは成功している為、警告のみとします
+//
+func (m *Manager) recordFiles(dir string) {
+
+	sums, err := hashTree(filepath.Join(m.root, dir))
+	if err != nil {
+		m.logger.Warn("hash files", "path", dir, "error", err)
+		return
+	}
+
+	err = m.saveFileSums(dir, sums)
+	if err != nil {
+		m.logger.Warn("save file list", "path", m.sumsPath(dir), "error", err)
+		return
+	}
+	m.logger.Debug("file list recorded", "path", m.sumsPath(dir), "files", len(sums))
+}
+
+//
+// saveFileSums is write the file list
+//
+// sha256sumと同じ形式(「{sha256}  {path}」)で書き込みます
+//
+func (m *Manager) saveFileSums(dir string, sums fileSums) error {
+
+	err := m.mkdirAll(filepath.Join(m.root, sumsDir))
+	if err != nil {
+		return classifyPermission(xerrors.Errorf("os.MkdirAll(): %w", err))
+	}
+
+	names := make([]string, 0, len(sums))
+	for name := range sums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s  %s\n", sums[name], name)
+	}
+
+	err = os.WriteFile(m.sumsPath(dir), []byte(b.String()), 0666)
+	if err != nil {
+		return classifyPermission(xerrors.Errorf("os.WriteFile(): %w", err))
+	}
+	m.chown(m.sumsPath(dir))
+	return nil
+}
+
+//
+// loadFileSums is read the file list
+//
+// 記録が存在しない場合はfs.ErrNotExistを返します
+//
+func (m *Manager) loadFileSums(dir string) (fileSums, error) {
+
+	f, err := os.Open(m.sumsPath(dir))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sums := make(fileSums)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		sum, name, ok := strings.Cut(line, "  ")
+		if !ok {
+			return nil, xerrors.Errorf("invalid file list line: %q", line)
+		}
+		sums[name] = sum
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("read file list: %w", err)
+	}
+	return sums, nil
+}
+
+//
+// hashTree is SHA256 of the regular files in the directory
+//
+// シンボリックリンク、ディレクトリは対象外です
+// rootがシンボリックリンクの場合(参照で取り込んだSDK)はリンク先を対象にします
+//
+func hashTree(root string) (fileSums, error) {
+
+	if p, err := filepath.EvalSymlinks(root); err == nil {
+		root = p
+	}
+
+	sums := make(fileSums)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		//開発版のビルドの記録
+		if rel == revisionFile {
+			return nil
+		}
+
+		sum, err := hashFile(p)
+		if err != nil {
+			return err
+		}
+		sums[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("filepath.WalkDir(): %w", err)
+	}
+	return sums, nil
+}
+
+// hashFile is SHA256 of the file
+func hashFile(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+//
+// Verify is integrity check of the installed version
+//
+// インストール時に記録したファイルの一覧と比較し、
+// 存在しない、内容が異なる、追加されたファイルを返します
+// 記録がない場合はリリースのアーカイブから一覧を作成します
+// repairを指定した場合は存在しない、内容が異なるファイルのみアーカイブから展開し直します
+//
+func (m *Manager) Verify(ctx context.Context, ver string, repair bool) (*VerifyResult, error) {
+
+	if m.root == "" {
+		return nil, errNoRoot
+	}
+
+	ver = canonicalVersion(ver)
+	if ver == "" || ver == m.linkName || filepath.Base(ver) != ver {
+		return nil, xerrors.Errorf("invalid version: %q", ver)
+	}
+
+	dir := filepath.Join(m.root, ver)
+	if _, err := os.Stat(dir); err != nil {
+		return nil, classify(ErrVersionNotFound, xerrors.Errorf("version not installed: %w", err))
+	}
+
+	//マニフェストのバージョン(ディレクトリ名と異なる場合がある)
+	v := NewVersion(ver)
+	if mf, err := m.Manifest(); err == nil {
+		if entry, ok := mf.SDKs[ver]; ok {
+			v = NewVersion(entry.Version)
+		}
+	}
+
+	rtn := VerifyResult{
+		Version: v,
+		Path:    dir,
+		Source:  m.sumsPath(ver),
+	}
+
+	url := m.source.ArchiveURL(v)
+	var archive *os.File
+
+	expected, err := m.loadFileSums(ver)
+	if errors.Is(err, fs.ErrNotExist) {
+		if ver == CompileSDK {
+			return nil, xerrors.Errorf("file list is not recorded: %s", ver)
+		}
+		//アーカイブから一覧を作成
+		m.logger.Info("file list is not recorded, use the archive", "version", v.String(), "url", url)
+		archive, err = m.downloadArchive(ctx, url)
+		if err != nil {
+			return nil, xerrors.Errorf("downloadArchive(): %w", err)
+		}
+		defer os.Remove(archive.Name())
+		defer archive.Close()
+
+		expected, err = archiveSums(ctx, archive, url)
+		if err != nil {
+			return nil, xerrors.Errorf("archiveSums(): %w", err)
+		}
+		rtn.Source = url
+
+		//次回からはアーカイブを利用しない
+		err = m.saveFileSums(ver, expected)
+		if err != nil {
+			m.logger.Warn("save file list", "path", m.sumsPath(ver), "error", err)
+		}
+	} else if err != nil {
+		return nil, xerrors.Errorf("loadFileSums(): %w", err)
+	}
+	rtn.Files = len(expected)
+
+	actual, err := hashTree(dir)
+	if err != nil {
+		return nil, classifyPermission(xerrors.Errorf("hashTree(): %w", err))
+	}
+
+	for name, sum := range expected {
+		now, ok := actual[name]
+		if !ok {
+			rtn.Missing = append(rtn.Missing, name)
+		} else if now != sum {
+			rtn.Modified = append(rtn.Modified, name)
+		}
+	}
+	for name := range actual {
+		if _, ok := expected[name]; !ok {
+			rtn.Extra = append(rtn.Extra, name)
+		}
+	}
+	sort.Strings(rtn.Missing)
+	sort.Strings(rtn.Modified)
+	sort.Strings(rtn.Extra)
+
+	m.logger.Info("verified", "version", ver, "files", rtn.Files,
+		"missing", len(rtn.Missing), "modified", len(rtn.Modified), "extra", len(rtn.Extra))
+
+	if !repair || rtn.OK() {
+		return &rtn, nil
+	}
+
+	if ver == CompileSDK {
+		return &rtn, xerrors.Errorf("development SDK cannot be repaired, run dev again")
+	}
+
+	if archive == nil {
+		archive, err = m.downloadArchive(ctx, url)
+		if err != nil {
+			return &rtn, xerrors.Errorf("downloadArchive(): %w", err)
+		}
+		defer os.Remove(archive.Name())
+		defer archive.Close()
+	}
+
+	damaged := make(map[string]bool)
+	for _, name := range append(rtn.Missing, rtn.Modified...) {
+		damaged[name] = true
+	}
+
+	rtn.Repaired, err = extractFiles(ctx, archive, url, dir, damaged)
+	if err != nil {
+		return &rtn, classifyContext(ctx, xerrors.Errorf("extractFiles(): %w", err))
+	}
+	m.logger.Info("repaired", "version", ver, "files", len(rtn.Repaired))
+
+	return &rtn, nil
+}
+
+//
+// downloadArchive is download the archive to a temporary file
+//
+// 公開されているチェックサムで検証してから返します
+// 呼び出し側で削除してください
+//
+func (m *Manager) downloadArchive(ctx context.Context, url string) (*os.File, error) {
+
+	resp, err := m.get(ctx, url, ErrVersionNotFound)
+	if err != nil {
+		return nil, xerrors.Errorf("http Get error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	sum, err := m.fetchChecksum(ctx, url)
+	if err != nil {
+		return nil, xerrors.Errorf("fetchChecksum() error: %w", err)
+	}
+
+	f, err := os.CreateTemp("", "golin-archive-*")
+	if err != nil {
+		return nil, xerrors.Errorf("os.CreateTemp(): %w", err)
+	}
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), resp.Body)
+	if err == nil {
+		actual := hex.EncodeToString(h.Sum(nil))
+		if sum != "" && actual != sum {
+			err = &ChecksumError{URL: url, Expected: sum, Actual: actual}
+		}
+	} else {
+		err = classifyRequest(ctx, xerrors.Errorf("download archive: %w", err))
+	}
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return f, nil
+}
+
+//
+// walkArchive is iterate the files in the archive
+//
+// 先頭の「go/」を除いた相対パスで通常のファイルのみを渡します
+//
+func walkArchive(ctx context.Context, f *os.File, url string, fn func(name string, mode fs.FileMode, r io.Reader) error) error {
+
+	_, err := f.Seek(0, io.SeekStart)
+	if err != nil {
+		return xerrors.Errorf("Seek(): %w", err)
+	}
+
+	switch getCompressType(url) {
+	case CompressZip:
+		info, err := f.Stat()
+		if err != nil {
+			return xerrors.Errorf("Stat(): %w", err)
+		}
+		zr, err := zip.NewReader(f, info.Size())
+		if err != nil {
+			return xerrors.Errorf("zip.NewReader() error: %w", err)
+		}
+		for _, zf := range zr.File {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			name := archiveName(zf.Name)
+			if name == "" || !zf.Mode().IsRegular() {
+				continue
+			}
+			r, err := zf.Open()
+			if err != nil {
+				return xerrors.Errorf("zip file open: %w", err)
+			}
+			err = fn(name, zf.Mode(), r)
+			r.Close()
+			if err != nil {
+				return err
+			}
+		}
+	case CompressTarGz:
+		gzr, err := gzip.NewReader(f)
+		if err != nil {
+			return xerrors.Errorf("gzip.NewReader() error: %w", err)
+		}
+		defer gzr.Close()
+
+		tr := tar.NewReader(gzr)
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			th, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return xerrors.Errorf("tar Next(): %w", err)
+			}
+			name := archiveName(th.Name)
+			if name == "" || th.Typeflag != tar.TypeReg {
+				continue
+			}
+			err = fn(name, fs.FileMode(th.Mode).Perm(), tr)
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("Decompress NotSupported: %s", url)
+	}
+	return nil
+}
+
+// archiveName is relative path in the SDK
+func archiveName(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "go" {
+		return ""
+	}
+	return strings.TrimPrefix(name, "go/")
+}
+
+// archiveSums is SHA256 of the files in the archive
+func archiveSums(ctx context.Context, f *os.File, url string) (fileSums, error) {
+	sums := make(fileSums)
+	err := walkArchive(ctx, f, url, func(name string, mode fs.FileMode, r io.Reader) error {
+		h := sha256.New()
+		if _, err := io.Copy(h, r); err != nil {
+			return xerrors.Errorf("read %s: %w", name, err)
+		}
+		sums[name] = hex.EncodeToString(h.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
+
+//
+// extractFiles is extract only the specified files
+//
+// 対象のファイルのみアーカイブから展開し、展開したファイルを返します
+//
+func extractFiles(ctx context.Context, f *os.File, url, dir string, targets map[string]bool) ([]string, error) {
+
+	rtn := make([]string, 0, len(targets))
+	err := walkArchive(ctx, f, url, func(name string, mode fs.FileMode, r io.Reader) error {
+		if !targets[name] {
+			return nil
+		}
+
+		fn := filepath.Join(dir, filepath.FromSlash(name))
+		err := checkPath(dir, fn)
+		if err != nil {
+			return xerrors.Errorf("checkPath(): %w", err)
+		}
+		err = os.MkdirAll(filepath.Dir(fn), 0777)
+		if err != nil {
+			return classifyPermission(xerrors.Errorf("make directory error: %w", err))
+		}
+
+		//読み取り専用のファイルを上書きする為に削除してから作成する
+		os.Remove(fn)
+		err = createTarFile(r, fn)
+		if err != nil {
+			return classifyPermission(xerrors.Errorf("createTarFile(): %w", err))
+		}
+		if mode != 0 {
+			err = os.Chmod(fn, mode)
+			if err != nil {
+				return xerrors.Errorf("os.Chmod(): %w", err)
+			}
+		}
+		rtn = append(rtn, name)
+		return nil
+	})
+	if err != nil {
+		return rtn, err
+	}
+	sort.Strings(rtn)
+	return rtn, nil
+}
@@ -1,23 +1,43 @@
 package golin
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/shizuokago/golin/v2/config"
 	"golang.org/x/xerrors"
 )
 
-// Version is r.v.m version
+//
+// Version is Go version
+//
+// Goプロジェクトのバージョンの文法(go/version)に従って解析します
+//
+//   1.21        言語バージョン(1.21rc1より前)
+//   1.21rc1     リリース候補(beta、alphaも同様)
+//   1.21.0      リリース
+//   1.20        1.21より前はパッチなしが最初のリリース(1.20.0と同じ)
+//   1.9.2rc2    パッチのリリース候補(1.9.2より前)
+//   go1.21.0    「go」の接頭辞は除きます
+//   1.22.0-20240101-abc  ツールチェインの接尾辞は比較では最後に評価します
+//   tip、gotip  開発版(すべてのバージョンより後)
+//
 type Version struct {
-	v    int
-	r    int
-	mean VersionMean
-	m    int
-	src  string
+	major  int
+	minor  int
+	patch  int //パッチがない場合は-1
+	mean   VersionMean
+	pre    int    //beta、rcの番号
+	suffix string //「-」以降
+	src    string
+
+	//バージョンの一覧から設定するメタデータ
+	date    time.Time     //リリース日
+	support SupportStatus //サポート状況
 }
 
 type VersionMean int
@@ -27,6 +47,8 @@ const (
 	RC
 	Beta
 	MeanError
+	Alpha
+	Tip
 )
 
 func (m VersionMean) String() string {
@@ -37,103 +59,223 @@ func (m VersionMean) String() string {
 		return "rc"
 	case Beta:
 		return "beta"
+	case Alpha:
+		return "alpha"
+	case Tip:
+		return "tip"
 	case MeanError:
 		return "Version Mean Error"
 	}
 	return "error(Mean not found)"
 }
 
-// Parse version string
-// src = "1.12.1" R,V,M
-// mean = major,rc,beta
-func NewVersion(src string) *Version {
-	v := &Version{
-		mean: Major,
-		src:  src,
-	}
-	var err error
-	slice := strings.Split(src, ".")
-	if len(slice) > 0 {
-		//バージョン部分を数値化
-		v.v, err = strconv.Atoi(slice[0])
-		//エラーがない場合
-		if len(slice) > 1 && err == nil {
-			//リビジョンを設定
-			r := slice[1]
-			err = v.setRevision(r)
-			if len(slice) > 2 && err == nil {
-				v.m, err = strconv.Atoi(slice[2])
-			}
-		}
+// rank is order of the meaning in the same patch
+//
+// 言語バージョン < alpha < beta < rc < リリース < 開発版
+func (m VersionMean) rank() int {
+	switch m {
+	case Alpha:
+		return 1
+	case Beta:
+		return 2
+	case RC:
+		return 3
+	case Major:
+		return 4
+	case Tip:
+		return 5
 	}
+	return -1
+}
 
+// prerelease is pre-release kinds
+var prerelease = []VersionMean{Alpha, Beta, RC}
+
+//
+// NewVersion is parse version string
+//
+// 解析できない場合はMeanErrorのバージョンを返します
+// エラーの内容が必要な場合はParse()を利用します
+//
+func NewVersion(src string) *Version {
+	v, err := Parse(src)
 	if err != nil {
-		v.mean = MeanError
+		return &Version{mean: MeanError, patch: -1, src: src}
 	}
 	return v
 }
 
-// setRevision
-func (v *Version) setRevision(r string) error {
-	key := Major
-	if strings.Index(r, "beta") != -1 {
-		key = Beta
-	} else if strings.Index(r, "rc") != -1 {
-		key = RC
+//
+// Parse is parse version string
+//
+// src = "1.21.0"、"1.21rc1"、"go1.21.0"、"tip"等
+//
+func Parse(src string) (*Version, error) {
+
+	v := Version{
+		mean:  Major,
+		patch: -1,
+		src:   src,
+	}
+
+	x := strings.TrimPrefix(src, "go")
+	if x == "tip" {
+		v.mean = Tip
+		return &v, nil
+	}
+
+	//ツールチェインの接尾辞(1.22.0-20240101-abc)
+	if idx := strings.Index(x, "-"); idx != -1 {
+		v.suffix = x[idx+1:]
+		x = x[:idx]
+		if v.suffix == "" {
+			return nil, xerrors.Errorf("invalid version %q: empty suffix", src)
+		}
+	}
+
+	var ok bool
+	v.major, x, ok = cutInt(x)
+	if !ok {
+		return nil, xerrors.Errorf("invalid version %q: major version", src)
+	}
+	//「1」は「1.0.0」
+	if x == "" {
+		v.patch = 0
+		return &v, nil
+	}
+
+	if x[0] != '.' {
+		return nil, xerrors.Errorf("invalid version %q: '.' expected after major version", src)
+	}
+	v.minor, x, ok = cutInt(x[1:])
+	if !ok {
+		return nil, xerrors.Errorf("invalid version %q: minor version", src)
 	}
 
-	var err error
-	if key == Major {
-		v.r, err = strconv.Atoi(r)
-	} else {
-		v.mean = key
-		slice := strings.Split(r, key.String())
-		if len(slice) == 2 {
-			v.r, err = strconv.Atoi(slice[0])
-			if err == nil {
-				v.m, err = strconv.Atoi(slice[1])
-			}
+	if x == "" {
+		//1.21より前はパッチなしが最初のリリース
+		if v.major == 1 && v.minor < 21 {
+			v.patch = 0
 		}
+		return &v, nil
+	}
+
+	if x[0] == '.' {
+		v.patch, x, ok = cutInt(x[1:])
+		if !ok {
+			return nil, xerrors.Errorf("invalid version %q: patch version", src)
+		}
+		if x == "" {
+			return &v, nil
+		}
+	}
+
+	for _, kind := range prerelease {
+		if strings.HasPrefix(x, kind.String()) {
+			v.mean = kind
+			x = x[len(kind.String()):]
+			break
+		}
+	}
+	if v.mean == Major {
+		return nil, xerrors.Errorf("invalid version %q: unknown pre-release %q", src, x)
+	}
+
+	v.pre, x, ok = cutInt(x)
+	if !ok || x != "" {
+		return nil, xerrors.Errorf("invalid version %q: pre-release number", src)
+	}
+	return &v, nil
+}
+
+// canonicalVersion is directory name of the version argument
+//
+// 「go1.21.0」は「1.21.0」、「gotip」は「tip」のように接頭辞を除きます
+// 解析できない名称(CompileSDK等)はそのまま返します
+func canonicalVersion(v string) string {
+	p, err := Parse(v)
+	if err != nil {
+		return v
 	}
+	return p.String()
+}
 
+// cutInt is cut the leading decimal number
+//
+// 0で始まる2桁以上の数値は許可しません
+func cutInt(x string) (int, string, bool) {
+	i := 0
+	for i < len(x) && '0' <= x[i] && x[i] <= '9' {
+		i++
+	}
+	if i == 0 || (x[0] == '0' && i != 1) || i > 9 {
+		return 0, x, false
+	}
+	n, err := strconv.Atoi(x[:i])
 	if err != nil {
-		v.mean = MeanError
+		return 0, x, false
 	}
-	return err
+	return n, x[i:], true
 }
 
+//
+// Compare is compare the versions
+//
+// src < target の場合は-1、同じ場合は0、src > target の場合は1を返します
+// 解析できないバージョンは解析できるバージョンより前とし、
+// 解析できないバージョン同士は文字列で比較します
+// Compare(a, b) == -Compare(b, a) が常に成り立ちます
+//
 func (src Version) Compare(target *Version) int {
 
-	if src.mean == MeanError {
-		return -1
-	} else if target.mean == MeanError {
-		return 1
+	if src.mean == MeanError || target.mean == MeanError {
+		if src.mean != MeanError {
+			return 1
+		} else if target.mean != MeanError {
+			return -1
+		}
+		return strings.Compare(src.src, target.src)
 	}
 
-	if src.v > target.v {
-		return 1
-	} else if src.v < target.v {
-		return -1
+	//開発版は常に最後
+	if src.mean == Tip || target.mean == Tip {
+		return compareInt(src.mean.rank(), target.mean.rank())
 	}
 
-	if src.r > target.r {
-		return 1
-	} else if src.r < target.r {
-		return -1
+	if c := compareInt(src.major, target.major); c != 0 {
+		return c
 	}
+	if c := compareInt(src.minor, target.minor); c != 0 {
+		return c
+	}
+	if c := compareInt(src.patch, target.patch); c != 0 {
+		return c
+	}
+	if c := compareInt(src.rank(), target.rank()); c != 0 {
+		return c
+	}
+	if c := compareInt(src.pre, target.pre); c != 0 {
+		return c
+	}
+	return strings.Compare(src.suffix, target.suffix)
+}
 
-	if src.mean > target.mean {
-		return 1
-	} else if src.mean < target.mean {
-		return -1
+// rank is order in the same patch
+//
+// パッチもプレリリースもない言語バージョン(1.21)はrcより前です
+func (v Version) rank() int {
+	if v.mean == Major && v.patch == -1 {
+		return 0
 	}
+	return v.mean.rank()
+}
 
-	if src.m > target.m {
-		return 1
-	} else if src.m < target.m {
+func compareInt(x, y int) int {
+	if x < y {
 		return -1
+	} else if x > y {
+		return 1
 	}
-
 	return 0
 }
 
@@ -145,21 +287,190 @@ func (src Version) Less(target *Version) bool {
 	return false
 }
 
+// String is version string
+//
+// 「go」の接頭辞は除きます(ディレクトリ名、URLに利用する為)
 func (v Version) String() string {
-	return v.src
+	if v.mean == MeanError {
+		return v.src
+	}
+	return strings.TrimPrefix(v.src, "go")
+}
+
+// Mean is version meaning
+//
+// バージョンとして解析できない場合はMeanErrorを返します
+func (v Version) Mean() VersionMean {
+	return v.mean
+}
+
+// Major is major version (1.21.0 -> 1)
+func (v Version) Major() int {
+	return v.major
+}
+
+// Minor is minor version (1.21.0 -> 21)
+func (v Version) Minor() int {
+	return v.minor
+}
+
+// Patch is patch version
+//
+// 1.21.0 -> 0、パッチがない場合(1.21rc1、1.21)は-1
+func (v Version) Patch() int {
+	return v.patch
+}
+
+// Prerelease is beta, rc or alpha
+func (v Version) Prerelease() bool {
+	return v.mean == RC || v.mean == Beta || v.mean == Alpha
+}
+
+// SupportStatus is support status of the minor version
+type SupportStatus int
+
+const (
+	SupportUnknown SupportStatus = iota //バージョンの一覧から判断できない
+	Supported                           //最新の2つのマイナーバージョン
+	Unsupported                         //サポートが終了したマイナーバージョン
+)
+
+func (s SupportStatus) String() string {
+	switch s {
+	case Supported:
+		return "supported"
+	case Unsupported:
+		return "unsupported"
+	}
+	return "unknown"
+}
+
+// ReleaseDate is release date
+//
+// バージョンの一覧から取得した場合のみ設定されます
+func (v Version) ReleaseDate() time.Time {
+	return v.date
+}
+
+// Support is support status
+//
+// バージョンの一覧から取得した場合のみ判断できます
+func (v Version) Support() SupportStatus {
+	return v.support
+}
+
+// releaseNotesURL is Go release notes
+const releaseNotesURL = "https://go.dev/doc"
+
+//
+// ReleaseNotes is release notes URL
+//
+// マイナーバージョン(1.21.0、1.21rc1)はリリースノート、
+// パッチバージョンはリリース履歴の該当箇所を返します
+// 開発版、解析できないバージョンは空文字です
+//
+func (v Version) ReleaseNotes() string {
+	if v.mean == MeanError || v.mean == Tip {
+		return ""
+	}
+	if v.patch > 0 {
+		return fmt.Sprintf("%s/devel/release#go%d.%d.%d", releaseNotesURL, v.major, v.minor, v.patch)
+	}
+	return fmt.Sprintf("%s/go%d.%d", releaseNotesURL, v.major, v.minor)
+}
+
+// supportedMinors is the two latest minor versions
+//
+// Goは最新の2つのマイナーバージョンをサポートします
+// beta、rcのみのマイナーバージョンは含みません
+func supportedMinors(list []*Version) []minorKey {
+	minors := make([]minorKey, 0, 2)
+	sorted := make([]*Version, len(list))
+	copy(sorted, list)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[j].Less(sorted[i])
+	})
+	for _, v := range sorted {
+		if v.mean != Major || v.patch < 0 {
+			continue
+		}
+		key := minorOf(v)
+		if len(minors) == 0 || minors[len(minors)-1] != key {
+			minors = append(minors, key)
+		}
+		if len(minors) == 2 {
+			break
+		}
+	}
+	return minors
+}
+
+// setSupport is set the support status
+func setSupport(v *Version, minors []minorKey) {
+	if v.mean == MeanError || v.mean == Tip || len(minors) == 0 {
+		v.support = SupportUnknown
+		return
+	}
+	v.support = Unsupported
+	for _, key := range minors {
+		if minorOf(v) == key {
+			v.support = Supported
+		}
+	}
+	//サポート中のマイナーバージョンより新しいbeta、rc
+	if v.support == Unsupported && minors[0].less(minorOf(v)) {
+		v.support = Supported
+	}
 }
 
 // GitHubのバージョン解析用のタグ
 const (
 	firstTag  = "div.Box-row"
 	secondTag = "a.js-navigation-open"
+	dateTag   = "relative-time"
 )
 
+// release is downloadable version
+//
+// dateはGitHubのディレクトリの更新日時で、取得できない場合はゼロ値です
+// securityはメタデータから判定できる場合のみ設定します
+type release struct {
+	version  *Version
+	date     time.Time
+	security bool //セキュリティリリースか
+}
+
 // GitHubページ(dl)からバージョンを確認して、
 // 可能なバージョンのスライスを取得
-func createVersionList() ([]*Version, error) {
+func (m *Manager) createVersionList(ctx context.Context) ([]*Version, error) {
+
+	releases, err := m.fetchReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := make([]*Version, 0, len(releases))
+	for _, elm := range releases {
+		v = append(v, elm.version)
+	}
+	return v, nil
+}
+
+// fetchReleases is downloadable versions with the date
+//
+// GitHubページ(dl)からバージョンとリリース日を取得します
+func (m *Manager) fetchReleases(ctx context.Context) ([]*release, error) {
 
-	doc, err := goquery.NewDocument(config.GitHubDownloadPage)
+	start := time.Now()
+	m.logger.Debug("fetch version list", "url", m.source.ListURL)
+
+	resp, err := m.get(ctx, m.source.ListURL, ErrNetwork)
+	if err != nil {
+		return nil, xerrors.Errorf("error github page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		return nil, xerrors.Errorf("error github page: %w", err)
 	}
@@ -172,7 +483,7 @@ func createVersionList() ([]*Version, error) {
 		return nil, xerrors.Errorf("Box-row is empty")
 	}
 
-	versionList := make([]string, 0, 100)
+	releases := make([]*release, 0, 100)
 	errs := make([]error, 0)
 
 	boxRow.Each(func(_ int, s *goquery.Selection) {
@@ -182,9 +493,16 @@ func createVersionList() ([]*Version, error) {
 			return
 		}
 		name := link.First().Text()
-		if isVersion(name) {
-			versionList = append(versionList, name[2:])
+		if !isVersion(name) {
+			return
+		}
+
+		r := release{version: NewVersion(name[2:])}
+		if dt, ok := s.Find(dateTag).First().Attr("datetime"); ok {
+			r.date, _ = time.Parse(time.RFC3339, dt)
+			r.version.date = r.date
 		}
+		releases = append(releases, &r)
 	})
 
 	if len(errs) > 0 {
@@ -192,23 +510,35 @@ func createVersionList() ([]*Version, error) {
 		for _, elm := range errs {
 			msg += "\n    " + elm.Error()
 		}
-		return nil, fmt.Errorf(msg)
+		return nil, xerrors.New(msg)
 	}
 
-	if len(versionList) <= 0 {
+	if len(releases) <= 0 {
 		return nil, fmt.Errorf("version not found.")
 	}
 
-	v := make([]*Version, 0, 100)
-	for _, elm := range versionList {
-		v = append(v, NewVersion(elm))
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].version.Less(releases[j].version)
+	})
+
+	versions := make([]*Version, len(releases))
+	for i, r := range releases {
+		versions[i] = r.version
+	}
+	minors := supportedMinors(versions)
+	for _, r := range releases {
+		setSupport(r.version, minors)
 	}
 
-	sort.Slice(v, func(i, j int) bool {
-		return v[i].Less(v[j])
-	})
+	//脆弱性のデータベースで修正されたバージョンをセキュリティリリースとする
+	if db := m.loadVulnDB(ctx); db != nil {
+		for _, r := range releases {
+			r.security = db.IsFix(r.version)
+		}
+	}
 
-	return v, nil
+	m.logger.Debug("version list", "url", m.source.ListURL, "versions", len(releases), "duration", time.Since(start))
+	return releases, nil
 }
 
 //バージョンを表すか？
@@ -230,9 +560,9 @@ func isVersion(name string) bool {
 }
 
 //最新のバージョンを取得
-func getLatestVersion() (*Version, error) {
+func (m *Manager) getLatestVersion(ctx context.Context) (*Version, error) {
 
-	list, err := createVersionList()
+	list, err := m.createVersionList(ctx)
 	if err != nil {
 		return nil, xerrors.Errorf("create version list error: %w", err)
 	}
@@ -246,5 +576,5 @@ func getLatestVersion() (*Version, error) {
 			return elm, nil
 		}
 	}
-	return nil, fmt.Errorf("Major version not found.")
+	return nil, classify(ErrVersionNotFound, xerrors.Errorf("Major version not found."))
 }
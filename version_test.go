@@ -34,3 +34,135 @@ func TestVersion(t *testing.T) {
 	}
 
 }
+
+func TestParse(t *testing.T) {
+
+	tests := []struct {
+		src   string
+		str   string
+		mean  golin.VersionMean
+		major int
+		minor int
+		patch int
+	}{
+		{"1.21.0", "1.21.0", golin.Major, 1, 21, 0},
+		{"1.21rc1", "1.21rc1", golin.RC, 1, 21, -1},
+		{"1.21", "1.21", golin.Major, 1, 21, -1},
+		{"1.20", "1.20", golin.Major, 1, 20, 0},
+		{"1.9.2rc2", "1.9.2rc2", golin.RC, 1, 9, 2},
+		{"1.18beta1", "1.18beta1", golin.Beta, 1, 18, -1},
+		{"go1.21.0", "1.21.0", golin.Major, 1, 21, 0},
+		{"1.22.0-20240101-abc", "1.22.0-20240101-abc", golin.Major, 1, 22, 0},
+		{"tip", "tip", golin.Tip, 0, 0, -1},
+		{"gotip", "tip", golin.Tip, 0, 0, -1},
+	}
+
+	for _, test := range tests {
+		v, err := golin.Parse(test.src)
+		if err != nil {
+			t.Errorf("Parse(%q) error[%v]", test.src, err)
+			continue
+		}
+		if v.String() != test.str || v.Mean() != test.mean ||
+			v.Major() != test.major || v.Minor() != test.minor || v.Patch() != test.patch {
+			t.Errorf("Parse(%q) [%s %v %d.%d.%d]", test.src, v, v.Mean(), v.Major(), v.Minor(), v.Patch())
+		}
+	}
+
+	for _, src := range []string{"", "go", "1.", "1.x", "1.21.", "1.21.0.1", "1.21rc", "1.21gamma1",
+		"1.021", "1.21-", "v1.21.0", "compile_sdk"} {
+		if _, err := golin.Parse(src); err == nil {
+			t.Errorf("Parse(%q) not error", src)
+		}
+		if v := golin.NewVersion(src); v.Mean() != golin.MeanError {
+			t.Errorf("NewVersion(%q) [%v]", src, v.Mean())
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+
+	//昇順
+	sorted := []string{
+		"invalid",
+		"1.9.1",
+		"1.9.2rc2",
+		"1.9.2",
+		"1.20beta1",
+		"1.20rc1",
+		"1.20",
+		"1.20.1",
+		"1.21",
+		"1.21rc1",
+		"1.21.0",
+		"1.22.0",
+		"1.22.0-20240101-abc",
+		"2.0beta1",
+		"tip",
+	}
+
+	for i, x := range sorted {
+		for j, y := range sorted {
+			vx := golin.NewVersion(x)
+			vy := golin.NewVersion(y)
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if c := vx.Compare(vy); c != want {
+				t.Errorf("Compare(%s, %s) [%d] != [%d]", x, y, c, want)
+			}
+		}
+	}
+
+	if golin.NewVersion("1.20").Compare(golin.NewVersion("1.20.0")) != 0 {
+		t.Errorf("1.20 != 1.20.0")
+	}
+	if golin.NewVersion("tip").Compare(golin.NewVersion("gotip")) != 0 {
+		t.Errorf("tip != gotip")
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{"1.21.0", "1.21rc1", "1.9.2rc2", "go1.21.0", "1.22.0-20240101-abc", "gotip", "1.x"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, src string) {
+		v, err := golin.Parse(src)
+		if err != nil {
+			if golin.NewVersion(src).Mean() != golin.MeanError {
+				t.Errorf("NewVersion(%q) is valid", src)
+			}
+			return
+		}
+		//Stringの結果は同じバージョンとして解析できる
+		again, err := golin.Parse(v.String())
+		if err != nil {
+			t.Fatalf("Parse(%q) error[%v]", v.String(), err)
+		}
+		if v.Compare(again) != 0 {
+			t.Errorf("Parse(%q) != Parse(%q)", src, v.String())
+		}
+	})
+}
+
+func FuzzCompare(f *testing.F) {
+	f.Add("1.21.0", "1.21rc1", "1.20")
+	f.Add("1.9.2rc2", "1.9.2", "tip")
+	f.Add("go1.22.0-x", "bad", "1.21")
+	f.Fuzz(func(t *testing.T, a, b, c string) {
+		va, vb, vc := golin.NewVersion(a), golin.NewVersion(b), golin.NewVersion(c)
+		if va.Compare(va) != 0 {
+			t.Errorf("Compare(%q, %q) != 0", a, a)
+		}
+		if va.Compare(vb) != -vb.Compare(va) {
+			t.Errorf("Compare(%q, %q) is not antisymmetric", a, b)
+		}
+		//推移律
+		if va.Compare(vb) <= 0 && vb.Compare(vc) <= 0 && va.Compare(vc) > 0 {
+			t.Errorf("Compare(%q, %q, %q) is not transitive", a, b, c)
+		}
+	})
+}
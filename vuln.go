@@ -0,0 +1,439 @@
+package golin
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// 標準ライブラリ、goコマンドの脆弱性のモジュール名(Go vulndb)
+const (
+	stdlibModule    = "stdlib"
+	toolchainModule = "toolchain"
+)
+
+// Vuln is known vulnerability of the Go SDK
+type Vuln struct {
+	ID       string   //GO-2023-1234
+	Aliases  []string //CVE等
+	Summary  string
+	URL      string   //詳細のページ
+	Fixed    string   //修正されたバージョン(同じマイナーバージョン、ない場合は空)
+	Packages []string //影響のあるパッケージ
+}
+
+// osvEntry is OSV format entry of Go vulndb
+type osvEntry struct {
+	ID        string   `json:"id"`
+	Summary   string   `json:"summary"`
+	Details   string   `json:"details"`
+	Aliases   []string `json:"aliases"`
+	Withdrawn string   `json:"withdrawn"`
+	Affected  []struct {
+		Package struct {
+			Name      string `json:"name"`
+			Ecosystem string `json:"ecosystem"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string `json:"type"`
+			Events []struct {
+				Introduced string `json:"introduced"`
+				Fixed      string `json:"fixed"`
+			} `json:"events"`
+		} `json:"ranges"`
+		EcosystemSpecific struct {
+			Imports []struct {
+				Path string `json:"path"`
+			} `json:"imports"`
+		} `json:"ecosystem_specific"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		URL string `json:"url"`
+	} `json:"database_specific"`
+}
+
+// vulnRange is affected range [introduced, fixed)
+type vulnRange struct {
+	introduced *Version //nilの場合は最初から
+	fixed      *Version //nilの場合は未修正
+}
+
+// vulnEntry is stdlib vulnerability
+type vulnEntry struct {
+	vuln   Vuln
+	ranges []vulnRange
+}
+
+// VulnDB is Go vulnerability database of the standard library
+//
+// Go vulndb(OSV形式)のうち標準ライブラリとgoコマンドの脆弱性のみを持ちます
+type VulnDB struct {
+	entries []*vulnEntry
+}
+
+//
+// LoadVulnDB is load the vulnerability database
+//
+// locationにはvulndbのミラー(index/modules.json、ID/{id}.json)のURL、
+// ミラーをコピーしたディレクトリ、OSVのJSONファイルを置いたディレクトリ、
+// OSVのJSON(エントリまたはエントリの配列)のファイルを指定します
+//
+func (m *Manager) LoadVulnDB(ctx context.Context, location string) (*VulnDB, error) {
+
+	var raws [][]byte
+	var err error
+
+	location = strings.TrimPrefix(location, "file://")
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		raws, err = m.fetchVulnDB(ctx, strings.TrimSuffix(location, "/"))
+	} else {
+		raws, err = readVulnDB(location)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("vulndb %s: %w", location, err)
+	}
+
+	db := VulnDB{}
+	for _, raw := range raws {
+		entries, err := parseOSV(raw)
+		if err != nil {
+			return nil, xerrors.Errorf("vulndb %s: %w", location, err)
+		}
+		for _, elm := range entries {
+			if e := newVulnEntry(elm); e != nil {
+				db.entries = append(db.entries, e)
+			}
+		}
+	}
+
+	sort.Slice(db.entries, func(i, j int) bool {
+		return db.entries[i].vuln.ID < db.entries[j].vuln.ID
+	})
+	m.logger.Debug("vulndb loaded", "location", location, "entries", len(db.entries))
+	return &db, nil
+}
+
+//
+// loadVulnDB is configured vulnerability database
+//
+// SetVulnDB(未指定の場合は環境変数GOVULNDB)のデータベースを一度だけ読み込みます
+// 指定がない場合、読み込みに失敗した場合(警告を出力)はnilを返します
+//
+func (m *Manager) loadVulnDB(ctx context.Context) *VulnDB {
+	if m.vulnDB == "" {
+		return nil
+	}
+	if m.vulnLoaded {
+		return m.vulns
+	}
+	db, err := m.LoadVulnDB(ctx, m.vulnDB)
+	if err != nil {
+		m.logger.Warn("load vulndb", "location", m.vulnDB, "error", err)
+		return nil
+	}
+	m.vulns = db
+	m.vulnLoaded = true
+	return db
+}
+
+// fetchVulnDB is stdlib entries from the vulndb mirror
+func (m *Manager) fetchVulnDB(ctx context.Context, base string) ([][]byte, error) {
+
+	index, err := m.fetchBytes(ctx, base+"/index/modules.json")
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := stdlibIDs(index)
+	if err != nil {
+		return nil, err
+	}
+
+	raws := make([][]byte, 0, len(ids))
+	for _, id := range ids {
+		raw, err := m.fetchBytes(ctx, base+"/ID/"+id+".json")
+		if err != nil {
+			return nil, err
+		}
+		raws = append(raws, raw)
+	}
+	return raws, nil
+}
+
+func (m *Manager) fetchBytes(ctx context.Context, url string) ([]byte, error) {
+	resp, err := m.get(ctx, url, ErrNetwork)
+	if err != nil {
+		return nil, xerrors.Errorf("http Get error: %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, classifyRequest(ctx, xerrors.Errorf("read %s: %w", url, err))
+	}
+	return b, nil
+}
+
+// stdlibIDs is stdlib vulnerability IDs in index/modules.json
+func stdlibIDs(index []byte) ([]string, error) {
+	var modules []struct {
+		Path  string `json:"path"`
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	}
+	err := json.Unmarshal(index, &modules)
+	if err != nil {
+		return nil, xerrors.Errorf("index/modules.json: %w", err)
+	}
+	ids := make([]string, 0)
+	for _, mod := range modules {
+		if mod.Path != stdlibModule && mod.Path != toolchainModule {
+			continue
+		}
+		for _, elm := range mod.Vulns {
+			ids = append(ids, elm.ID)
+		}
+	}
+	return ids, nil
+}
+
+//
+// readVulnDB is JSON files in the local path
+//
+// ミラーの形式(index/modules.jsonがある)の場合は標準ライブラリのエントリのみ、
+// それ以外はディレクトリ以下のすべてのJSONを読み込みます
+//
+func readVulnDB(location string) ([][]byte, error) {
+
+	info, err := os.Stat(location)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		b, err := os.ReadFile(location)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{b}, nil
+	}
+
+	if index, err := os.ReadFile(filepath.Join(location, "index", "modules.json")); err == nil {
+		ids, err := stdlibIDs(index)
+		if err != nil {
+			return nil, err
+		}
+		raws := make([][]byte, 0, len(ids))
+		for _, id := range ids {
+			b, err := os.ReadFile(filepath.Join(location, "ID", id+".json"))
+			if err != nil {
+				return nil, err
+			}
+			raws = append(raws, b)
+		}
+		return raws, nil
+	}
+
+	raws := make([][]byte, 0)
+	err = filepath.WalkDir(location, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".json" {
+			return nil
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		raws = append(raws, b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return raws, nil
+}
+
+// parseOSV is OSV entry or entries
+//
+// idを持たないJSON(インデックス等)は無視します
+func parseOSV(raw []byte) ([]*osvEntry, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if strings.HasPrefix(trimmed, "[") {
+		var list []*osvEntry
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+	var entry osvEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, err
+	}
+	if entry.ID == "" {
+		return nil, nil
+	}
+	return []*osvEntry{&entry}, nil
+}
+
+// newVulnEntry is stdlib vulnerability of the OSV entry
+//
+// 取り下げられたもの、標準ライブラリ以外のものはnilを返します
+func newVulnEntry(osv *osvEntry) *vulnEntry {
+
+	if osv == nil || osv.ID == "" || osv.Withdrawn != "" {
+		return nil
+	}
+
+	e := vulnEntry{
+		vuln: Vuln{
+			ID:      osv.ID,
+			Aliases: osv.Aliases,
+			Summary: osv.Summary,
+			URL:     osv.DatabaseSpecific.URL,
+		},
+	}
+	if e.vuln.URL == "" {
+		e.vuln.URL = "https://pkg.go.dev/vuln/" + osv.ID
+	}
+
+	for _, aff := range osv.Affected {
+		name := aff.Package.Name
+		if name != stdlibModule && name != toolchainModule {
+			continue
+		}
+		for _, imp := range aff.EcosystemSpecific.Imports {
+			e.vuln.Packages = append(e.vuln.Packages, imp.Path)
+		}
+		for _, rng := range aff.Ranges {
+			if rng.Type != "SEMVER" {
+				continue
+			}
+			for _, ev := range rng.Events {
+				if ev.Introduced != "" {
+					e.ranges = append(e.ranges, vulnRange{introduced: semverVersion(ev.Introduced)})
+				} else if ev.Fixed != "" && len(e.ranges) > 0 {
+					e.ranges[len(e.ranges)-1].fixed = semverVersion(ev.Fixed)
+				}
+			}
+		}
+	}
+
+	if len(e.ranges) == 0 {
+		return nil
+	}
+	return &e
+}
+
+//
+// semverVersion is Go version of the vulndb semver
+//
+// vulndbは標準ライブラリのバージョンをsemverで表します
+//
+//   0              nil(最初から)
+//   1.21.0-rc.1    1.21rc1
+//   1.9.2-rc.2     1.9.2rc2
+//   1.21.0-0       1.21(すべてのプレリリースより前)
+//
+func semverVersion(s string) *Version {
+
+	s = strings.TrimPrefix(s, "v")
+	if s == "0" || s == "" {
+		return nil
+	}
+
+	base, pre := s, ""
+	if idx := strings.Index(s, "-"); idx != -1 {
+		base, pre = s[:idx], s[idx+1:]
+	}
+	v := NewVersion(base)
+	if pre == "" || v.mean == MeanError {
+		return v
+	}
+
+	minor := strings.Join(strings.SplitN(base, ".", 3)[:2], ".")
+	if pre == "0" {
+		if v.patch == 0 {
+			return NewVersion(minor)
+		}
+		return NewVersion(base + "alpha0")
+	}
+
+	//rc.1 -> rc1
+	pre = strings.Replace(pre, ".", "", 1)
+	if v.patch == 0 {
+		return NewVersion(minor + pre)
+	}
+	return NewVersion(base + pre)
+}
+
+// affects is version in the range
+func (r vulnRange) affects(v *Version) bool {
+	if r.introduced != nil && v.Less(r.introduced) {
+		return false
+	}
+	if r.fixed != nil && !v.Less(r.fixed) {
+		return false
+	}
+	return true
+}
+
+//
+// Affecting is vulnerabilities affecting the version
+//
+// Fixedには同じマイナーバージョンで修正されたバージョンを設定します
+//
+func (db *VulnDB) Affecting(v *Version) []*Vuln {
+
+	if db == nil || v.mean == MeanError || v.mean == Tip {
+		return nil
+	}
+
+	rtn := make([]*Vuln, 0)
+	for _, e := range db.entries {
+		for _, r := range e.ranges {
+			if !r.affects(v) {
+				continue
+			}
+			vuln := e.vuln
+			if r.fixed != nil && minorOf(r.fixed) == minorOf(v) {
+				vuln.Fixed = r.fixed.String()
+			}
+			rtn = append(rtn, &vuln)
+			break
+		}
+	}
+	return rtn
+}
+
+// IsFix is the version fixes a vulnerability
+//
+// 脆弱性が修正されたバージョン(セキュリティリリース)の場合にtrueを返します
+func (db *VulnDB) IsFix(v *Version) bool {
+	if db == nil {
+		return false
+	}
+	for _, e := range db.entries {
+		for _, r := range e.ranges {
+			if r.fixed != nil && r.fixed.Compare(v) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Len is number of the vulnerabilities
+func (db *VulnDB) Len() int {
+	if db == nil {
+		return 0
+	}
+	return len(db.entries)
+}